@@ -0,0 +1,44 @@
+package bitbucket
+
+const (
+	AccountTypeUser    = "user"
+	AccountTypeTeam    = "team"
+	AccountTypeAppUser = "app_user"
+)
+
+type AccountLinks struct {
+	Self   Link
+	Html   Link
+	Avatar Link
+}
+
+// Account holds the fields shared by users and teams, as embedded in other resources, ie. the owner of a
+// repository. Uuid and Account_id are stable, while Username and Nickname may change.
+type Account struct {
+	Type         string
+	Uuid         string
+	Account_id   string
+	Username     string
+	Nickname     string
+	Display_name string
+	Created_on   string
+	Website      string
+	Location     string
+	Links        AccountLinks
+}
+
+// IsUser reports whether the account belongs to a person.
+func (a *Account) IsUser() bool {
+	return a.Type == AccountTypeUser
+}
+
+// IsTeam reports whether the account is a team, ie. a workspace.
+func (a *Account) IsTeam() bool {
+	return a.Type == AccountTypeTeam
+}
+
+// Team is a team account. Teams were replaced by workspaces; the typed Teams calls fill it from the workspace
+// with the same UUID.
+type Team struct {
+	Account `mapstructure:",squash"`
+}
@@ -1,5 +1,10 @@
 package bitbucket
 
+import (
+	"io"
+	"time"
+)
+
 var apiBaseURL = "https://api.bitbucket.org/2.0"
 
 func GetApiBaseURL() string {
@@ -10,8 +15,19 @@ func SetApiBaseURL(urlStr string) {
 	apiBaseURL = urlStr
 }
 
+// Bool returns a pointer to v, for the optional fields of the options structs where false and unset differ.
+func Bool(v bool) *bool {
+	return &v
+}
+
+// Int returns a pointer to v, for the optional fields of the options structs where 0 and unset differ.
+func Int(v int) *int {
+	return &v
+}
+
 type users interface {
 	Get(username string) (interface{}, error)
+	GetUser(username string) (*User, error)
 	Followers(username string) (interface{}, error)
 	Following(username string) (interface{}, error)
 	Repositories(username string) (interface{}, error)
@@ -19,7 +35,20 @@ type users interface {
 
 type user interface {
 	Profile() (interface{}, error)
+	GetProfile() (*User, error)
 	Emails() (interface{}, error)
+	ListEmails() ([]*Email, error)
+	GetEmail(email string) (*Email, error)
+	ListSSHKeys(opt *UserKeyOptions) ([]*SSHKey, error)
+	GetSSHKey(opt *UserKeyOptions) (*SSHKey, error)
+	AddSSHKey(opt *UserKeyOptions) (*SSHKey, error)
+	UpdateSSHKey(opt *UserKeyOptions) (*SSHKey, error)
+	DeleteSSHKey(opt *UserKeyOptions) error
+	ListGPGKeys(opt *UserKeyOptions) ([]*GPGKey, error)
+	GetGPGKey(opt *UserKeyOptions) (*GPGKey, error)
+	AddGPGKey(opt *UserKeyOptions) (*GPGKey, error)
+	DeleteGPGKey(opt *UserKeyOptions) error
+	RepositoryPermissions(permission string) ([]*RepositoryPermission, error)
 }
 
 type pullrequests interface {
@@ -30,8 +59,11 @@ type pullrequests interface {
 	Activities(opt PullRequestsOptions) (interface{}, error)
 	Activity(opt PullRequestsOptions) (interface{}, error)
 	Commits(opt PullRequestsOptions) (interface{}, error)
-	Patch(opt PullRequestsOptions) (interface{}, error)
-	Diff(opt PullRequestsOptions) (interface{}, error)
+	Patch(opt PullRequestsOptions) ([]byte, error)
+	PatchCommits(opt PullRequestsOptions) ([]*PatchCommit, error)
+	Diff(opt PullRequestsOptions) ([]byte, error)
+	DiffFiles(opt PullRequestsOptions) ([]*DiffFile, error)
+	Diffstat(opt PullRequestsOptions) ([]*Diffstat, error)
 	Merge(opt PullRequestsOptions) (interface{}, error)
 	Decline(opt PullRequestsOptions) (interface{}, error)
 }
@@ -39,18 +71,50 @@ type pullrequests interface {
 type repository interface {
 	Get(opt RepositoryOptions) (*Repository, error)
 	Create(opt RepositoryOptions) (*Repository, error)
+	Update(opt RepositoryOptions) (*Repository, error)
+	Rename(opt RepositoryOptions, name string) (*Repository, error)
+	TransferToProject(opt RepositoryOptions, projectKey string) (*Repository, error)
 	Delete(opt RepositoryOptions) (interface{}, error)
 	ListWatchers(opt RepositoryOptions) (interface{}, error)
-	ListForks(opt RepositoryOptions) (interface{}, error)
+	ListForks(opt RepositoryOptions) (*RepositoriesPage, error)
+	CreateFork(opt RepositoryForkOptions) (*Repository, error)
+	GetForkTree(opt RepositoryOptions) (*ForkNode, error)
 	UpdatePipelineConfig(opt RepositoryPipelineOptions) (*Pipeline, error)
 	AddPipelineVariable(opt RepositoryPipelineVariableOptions) (*PipelineVariable, error)
+	ListPipelineVariables(opt RepositoryPipelineVariableOptions) ([]*PipelineVariable, error)
+	GetPipelineVariable(opt RepositoryPipelineVariableOptions) (*PipelineVariable, error)
+	UpdatePipelineVariable(opt RepositoryPipelineVariableOptions) (*PipelineVariable, error)
+	DeletePipelineVariable(opt RepositoryPipelineVariableOptions) error
+	UpsertPipelineVariable(opt RepositoryPipelineVariableOptions) (*PipelineVariable, error)
 	AddPipelineKeyPair(opt RepositoryPipelineKeyPairOptions) (*PipelineKeyPair, error)
+	GetPipelineKeyPair(opt RepositoryPipelineKeyPairOptions) (*PipelineKeyPair, error)
+	DeletePipelineKeyPair(opt RepositoryPipelineKeyPairOptions) error
+	GeneratePipelineKeyPair(opt RepositoryPipelineKeyPairOptions, keyType string) (*PipelineKeyPair, error)
+	ListPipelineSchedules(opt RepositoryPipelineScheduleOptions) ([]*PipelineSchedule, error)
+	GetPipelineSchedule(opt RepositoryPipelineScheduleOptions) (*PipelineSchedule, error)
+	AddPipelineSchedule(opt RepositoryPipelineScheduleOptions) (*PipelineSchedule, error)
+	UpdatePipelineSchedule(opt RepositoryPipelineScheduleOptions) (*PipelineSchedule, error)
+	DeletePipelineSchedule(opt RepositoryPipelineScheduleOptions) error
+	ListPipelineCaches(opt RepositoryPipelineCacheOptions) ([]*PipelineCache, error)
+	DeletePipelineCache(opt RepositoryPipelineCacheOptions) error
+	ListPipelineKnownHosts(opt RepositoryPipelineKnownHostOptions) ([]*PipelineKnownHost, error)
+	GetPipelineKnownHost(opt RepositoryPipelineKnownHostOptions) (*PipelineKnownHost, error)
+	AddPipelineKnownHost(opt RepositoryPipelineKnownHostOptions) (*PipelineKnownHost, error)
+	UpdatePipelineKnownHost(opt RepositoryPipelineKnownHostOptions) (*PipelineKnownHost, error)
+	DeletePipelineKnownHost(opt RepositoryPipelineKnownHostOptions) error
+	GetFile(opt RepositoryOptions, filePath, hash string) ([]byte, error)
+	GetMainBranch(opt RepositoryOptions) (string, error)
+	ListFiles(opt RepositoryFilesOptions) ([]*CommitFile, error)
+	GetFileMeta(opt RepositoryFilesOptions) (*CommitFile, error)
+	CommitFiles(opt RepositoryCommitOptions) (string, error)
+	FileHistory(opt RepositoryFilesOptions) *FileHistory
 }
 
 type repositories interface {
-	ListForAccount(opt RepositoriesOptions) (interface{}, error)
-	ListForTeam(opt RepositoriesOptions) (interface{}, error)
-	ListPublic() (interface{}, error)
+	ListForAccount(opt RepositoriesOptions) (*RepositoriesPage, error)
+	ListForTeam(opt RepositoriesOptions) (*RepositoriesPage, error)
+	ListPublic() (*RepositoriesPage, error)
+	ListForProject(opt ProjectRepositoryOptions) (*RepositoriesPage, error)
 }
 
 type commits interface {
@@ -72,8 +136,11 @@ type branchrestrictions interface {
 }
 
 type diff interface {
-	GetDiff(opt DiffOptions) (interface{}, error)
-	GetPatch(opt DiffOptions) (interface{}, error)
+	GetDiff(opt DiffOptions) ([]byte, error)
+	GetDiffFiles(opt DiffOptions) ([]*DiffFile, error)
+	GetPatch(opt DiffOptions) ([]byte, error)
+	GetPatchCommits(opt DiffOptions) ([]*PatchCommit, error)
+	GetDiffstat(opt DiffOptions) ([]*Diffstat, error)
 }
 
 type webhooks interface {
@@ -84,10 +151,107 @@ type webhooks interface {
 	Delete(opt WebhooksOptions) (interface{}, error)
 }
 
+type refs interface {
+	ListBranches(opt RefsOptions) ([]*Branch, error)
+	GetBranch(opt RefsOptions) (*Branch, error)
+	CreateBranch(opt RefsOptions) (*Branch, error)
+	DeleteBranch(opt RefsOptions) error
+	ListTags(opt RefsOptions) ([]*Tag, error)
+	GetTag(opt RefsOptions) (*Tag, error)
+	CreateTag(opt RefsOptions) (*Tag, error)
+	DeleteTag(opt RefsOptions) error
+}
+
+type permissions interface {
+	ListUsers(opt RepositoryPermissionsOptions) ([]*RepositoryUserPermission, error)
+	GetUser(opt RepositoryPermissionsOptions) (*RepositoryUserPermission, error)
+	UpdateUser(opt RepositoryPermissionsOptions) (*RepositoryUserPermission, error)
+	DeleteUser(opt RepositoryPermissionsOptions) error
+	ListGroups(opt RepositoryPermissionsOptions) ([]*RepositoryGroupPermission, error)
+	GetGroup(opt RepositoryPermissionsOptions) (*RepositoryGroupPermission, error)
+	UpdateGroup(opt RepositoryPermissionsOptions) (*RepositoryGroupPermission, error)
+	DeleteGroup(opt RepositoryPermissionsOptions) error
+}
+
+type deploykeys interface {
+	List(opt DeployKeyOptions) ([]*DeployKey, error)
+	Get(opt DeployKeyOptions) (*DeployKey, error)
+	Create(opt DeployKeyOptions) (*DeployKey, error)
+	Update(opt DeployKeyOptions) (*DeployKey, error)
+	Delete(opt DeployKeyOptions) error
+	ListForProject(opt DeployKeyOptions) ([]*DeployKey, error)
+	GetForProject(opt DeployKeyOptions) (*DeployKey, error)
+	CreateForProject(opt DeployKeyOptions) (*DeployKey, error)
+	DeleteForProject(opt DeployKeyOptions) error
+}
+
+type pipelines interface {
+	Trigger(opt PipelinesOptions) (*PipelineRun, error)
+	List(opt PipelinesOptions) ([]*PipelineRun, error)
+	Get(opt PipelinesOptions) (*PipelineRun, error)
+	Stop(opt PipelinesOptions) error
+	Wait(opt PipelinesOptions, interval, timeout time.Duration) (*PipelineRun, error)
+	ListSteps(opt PipelinesOptions) ([]*PipelineStep, error)
+	GetStep(opt PipelinesOptions) (*PipelineStep, error)
+	GetStepLog(opt PipelinesOptions, offset int64) ([]byte, error)
+	StepLogReader(opt PipelinesOptions, offset int64) (io.ReadCloser, error)
+	GetTestReport(opt PipelinesOptions) (*PipelineTestReport, error)
+	ListTestCases(opt PipelinesOptions) ([]*PipelineTestCase, error)
+	ListTestCaseReasons(opt PipelinesOptions) ([]*PipelineTestCaseReason, error)
+	ListVariables(opt PipelineVariableOptions) ([]*PipelineVariable, error)
+	GetVariable(opt PipelineVariableOptions) (*PipelineVariable, error)
+	CreateVariable(opt PipelineVariableOptions) (*PipelineVariable, error)
+	UpdateVariable(opt PipelineVariableOptions) (*PipelineVariable, error)
+	DeleteVariable(opt PipelineVariableOptions) error
+	UpsertVariable(opt PipelineVariableOptions) (*PipelineVariable, error)
+}
+
+type deployments interface {
+	ListEnvironments(opt DeploymentsOptions) ([]*Environment, error)
+	GetEnvironment(opt DeploymentsOptions) (*Environment, error)
+	CreateEnvironment(opt DeploymentsOptions) (*Environment, error)
+	UpdateEnvironment(opt DeploymentsOptions) (*Environment, error)
+	DeleteEnvironment(opt DeploymentsOptions) error
+	List(opt DeploymentsOptions) ([]*Deployment, error)
+	Get(opt DeploymentsOptions) (*Deployment, error)
+}
+
+type projects interface {
+	List(opt ProjectOptions) ([]*Project, error)
+	Get(opt ProjectOptions) (*Project, error)
+	Create(opt ProjectOptions) (*Project, error)
+	Update(opt ProjectOptions) (*Project, error)
+	Delete(opt ProjectOptions) error
+	ListUserPermissions(opt ProjectOptions) ([]*ProjectUserPermission, error)
+	GetUserPermission(opt ProjectOptions) (*ProjectUserPermission, error)
+	UpdateUserPermission(opt ProjectOptions) (*ProjectUserPermission, error)
+	DeleteUserPermission(opt ProjectOptions) error
+	ListGroupPermissions(opt ProjectOptions) ([]*ProjectGroupPermission, error)
+	GetGroupPermission(opt ProjectOptions) (*ProjectGroupPermission, error)
+	UpdateGroupPermission(opt ProjectOptions) (*ProjectGroupPermission, error)
+	DeleteGroupPermission(opt ProjectOptions) error
+	ListDefaultReviewers(opt ProjectOptions) ([]*DefaultReviewer, error)
+	AddDefaultReviewer(opt ProjectOptions) error
+	RemoveDefaultReviewer(opt ProjectOptions) error
+}
+
+type workspaces interface {
+	List(opt WorkspaceOptions) ([]*Workspace, error)
+	Get(opt WorkspaceOptions) (*Workspace, error)
+	ListMembers(opt WorkspaceOptions) ([]*WorkspaceMembership, error)
+	GetMember(opt WorkspaceOptions) (*WorkspaceMembership, error)
+	ListPermissions(opt WorkspaceOptions) ([]*WorkspaceMembership, error)
+	ListRepositoryPermissions(opt WorkspaceOptions) ([]*RepositoryPermission, error)
+	ListProjects(opt WorkspaceOptions) ([]*Project, error)
+	ListRepositories(opt WorkspaceOptions) (*RepositoriesPage, error)
+}
+
 type teams interface {
 	List(role string) (interface{}, error) // [WIP?] role=[admin|contributor|member]
 	Profile(teamname string) (interface{}, error)
+	GetProfile(teamname string) (*Team, error)
 	Members(teamname string) (interface{}, error)
+	GetMembers(teamname string) ([]*User, error)
 	Followers(teamname string) (interface{}, error)
 	Following(teamname string) (interface{}, error)
 	Repositories(teamname string) (interface{}, error)
@@ -102,18 +266,84 @@ type RepositoriesOptions struct {
 	Role  string `json:"role"` // role=[owner|admin|contributor|member]
 }
 
+type UserKeyOptions struct {
+	Owner       string `json:"owner"` // account id or UUID, the authenticated user if empty
+	Uuid        string `json:"uuid"`  // SSH key UUID
+	Fingerprint string `json:"fingerprint"`
+	Key         string `json:"key"`
+	Label       string `json:"label"`
+}
+
+type WorkspaceOptions struct {
+	Owner     string `json:"owner"`  // workspace slug or UUID
+	Member    string `json:"member"` // account id or UUID
+	Repo_slug string `json:"repo_slug"`
+	Role      string `json:"role"`
+	Query     string `json:"q"`
+	Sort      string `json:"sort"`
+}
+
+// RepositoryOptions selects a repository by Owner and Repo_slug. The remaining fields are only sent on Create
+// and Update when set; the *bool fields are nil for "leave unchanged", see Bool.
 type RepositoryOptions struct {
+	Owner       string `json:"owner"`
+	Repo_slug   string `json:"repo_slug"`
+	Scm         string `json:"scm"`
+	Name        string `json:"name"`
+	Is_private  *bool  `json:"is_private"`
+	Description string `json:"description"`
+	Fork_policy string `json:"fork_policy"` // allow_forks, no_public_forks or no_forks
+	Language    string `json:"language"`
+	Has_issues  *bool  `json:"has_issues"`
+	Has_wiki    *bool  `json:"has_wiki"`
+	Project     string `json:"project"`    // project key
+	Mainbranch  string `json:"mainbranch"` // branch name
+}
+
+// RepositoryForkOptions selects the repository to fork by Owner and Repo_slug. The other fields describe the
+// fork and default to the values of the forked repository.
+type RepositoryForkOptions struct {
+	Owner       string `json:"owner"`
+	Repo_slug   string `json:"repo_slug"`
+	Workspace   string `json:"workspace"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Is_private  *bool  `json:"is_private"`
+	Fork_policy string `json:"fork_policy"`
+	Project     string `json:"project"` // project key in the target workspace
+}
+
+type RepositoryFilesOptions struct {
 	Owner     string `json:"owner"`
 	Repo_slug string `json:"repo_slug"`
-	Scm       string `json:"scm"`
-	//	Name        string `json:"name"`
-	Is_private  string `json:"is_private"`
+	Revision  string `json:"revision"` // commit hash or branch, defaults to the main branch
+	Path      string `json:"path"`
+	Max_depth int    `json:"max_depth"`
+	Query     string `json:"q"`
+	Sort      string `json:"sort"`
+}
+
+type RepositoryCommitOptions struct {
+	Owner        string            `json:"owner"`
+	Repo_slug    string            `json:"repo_slug"`
+	Branch       string            `json:"branch"`
+	Message      string            `json:"message"`
+	Author       string            `json:"author"`  // "Name <email>", defaults to the authenticated user
+	Parents      []string          `json:"parents"` // defaults to the head of Branch
+	Files        map[string][]byte `json:"files"`   // path to content of the files to add or update
+	Delete_files []string          `json:"delete_files"`
+}
+
+type ProjectOptions struct {
+	Owner       string `json:"owner"` // workspace
+	Key         string `json:"key"`
+	Name        string `json:"name"`
 	Description string `json:"description"`
-	Fork_policy string `json:"fork_policy"`
-	Language    string `json:"language"`
-	Has_issues  string `json:"has_issues"`
-	Has_wiki    string `json:"has_wiki"`
-	Project     string `json:"project"`
+	Is_private  *bool  `json:"is_private"`
+	Avatar      string `json:"avatar"`     // image URL or data URL
+	User        string `json:"user"`       // account id or UUID
+	Group       string `json:"group"`      // group slug
+	Permission  string `json:"permission"` // read, write, create-repo or admin
 }
 
 type ProjectRepositoryOptions struct {
@@ -137,6 +367,12 @@ type PullRequestsOptions struct {
 	Destination_commit  string   `json:"destination_repository"`
 	Message             string   `json:"message"`
 	Reviewers           []string `json:"reviewers"`
+	Path                []string `json:"path"`
+	Context             *int     `json:"context"`
+	Ignore_whitespace   bool     `json:"ignore_whitespace"`
+	Binary              *bool    `json:"binary"`
+	Renames             *bool    `json:"renames"`
+	Topic               *bool    `json:"topic"`
 }
 
 type CommitsOptions struct {
@@ -163,9 +399,42 @@ type BranchRestrictionsOptions struct {
 }
 
 type DiffOptions struct {
+	Owner             string   `json:"owner"`
+	Repo_slug         string   `json:"repo_slug"`
+	Spec              string   `json:"spec"`
+	Path              []string `json:"path"`
+	Context           *int     `json:"context"`
+	Ignore_whitespace bool     `json:"ignore_whitespace"`
+	Binary            *bool    `json:"binary"`  // defaults to true
+	Renames           *bool    `json:"renames"` // defaults to true
+	Topic             *bool    `json:"topic"`   // diff against the merge base instead of the merge result
+}
+
+type RefsOptions struct {
+	Owner     string `json:"owner"`
+	Repo_slug string `json:"repo_slug"`
+	Name      string `json:"name"`
+	Target    string `json:"target"`  // commit hash the new branch or tag points at
+	Message   string `json:"message"` // annotates a new tag
+	Query     string `json:"q"`       // BBQL filter for the list calls
+	Sort      string `json:"sort"`
+}
+
+type RepositoryPermissionsOptions struct {
+	Owner      string `json:"owner"`
+	Repo_slug  string `json:"repo_slug"`
+	User       string `json:"user"`       // account id or UUID
+	Group      string `json:"group"`      // group slug
+	Permission string `json:"permission"` // read, write or admin
+}
+
+type DeployKeyOptions struct {
 	Owner     string `json:"owner"`
 	Repo_slug string `json:"repo_slug"`
-	Spec      string `json:"spec"`
+	Project   string `json:"project"` // project key
+	Id        int    `json:"id"`
+	Key       string `json:"key"`
+	Label     string `json:"label"`
 }
 
 type WebhooksOptions struct {
@@ -184,6 +453,47 @@ type RepositoryPipelineOptions struct {
 	Enabled   bool   `json:"has_pipelines"`
 }
 
+// PipelinesOptions selects pipeline runs. The target fields describe what to run on Trigger and filter the
+// runs on List.
+type PipelinesOptions struct {
+	Owner            string             `json:"owner"`
+	Repo_slug        string             `json:"repo_slug"`
+	Uuid             string             `json:"uuid"`
+	Step_uuid        string             `json:"step_uuid"`
+	Test_case_uuid   string             `json:"test_case_uuid"`
+	Ref_type         string             `json:"ref_type"` // branch or tag
+	Ref_name         string             `json:"ref_name"`
+	Commit           string             `json:"commit"` // commit hash
+	Selector_type    string             `json:"selector_type"`
+	Selector_pattern string             `json:"selector_pattern"`
+	Variables        []PipelineVariable `json:"variables"`
+	Status           string             `json:"status"`
+	Sort             string             `json:"sort"`
+}
+
+// PipelineVariableOptions selects the variable and its Scope, one of the PipelineVariableScope constants. The
+// Environment is given by UUID.
+type PipelineVariableOptions struct {
+	Scope       string `json:"scope"`
+	Owner       string `json:"owner"`
+	Repo_slug   string `json:"repo_slug"`
+	Environment string `json:"environment"`
+	Uuid        string `json:"uuid"`
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Secured     bool   `json:"secured"`
+}
+
+type DeploymentsOptions struct {
+	Owner            string `json:"owner"`
+	Repo_slug        string `json:"repo_slug"`
+	Uuid             string `json:"uuid"`        // deployment UUID
+	Environment      string `json:"environment"` // environment UUID
+	Name             string `json:"name"`
+	Environment_type string `json:"environment_type"` // Test, Staging or Production
+	Admin_only       *bool  `json:"admin_only"`
+}
+
 type RepositoryPipelineVariableOptions struct {
 	Owner     string `json:"owner"`
 	Repo_slug string `json:"repo_slug"`
@@ -199,3 +509,30 @@ type RepositoryPipelineKeyPairOptions struct {
 	Private_key string `json:"private_key"`
 	Public_key  string `json:"public_key"`
 }
+
+type RepositoryPipelineScheduleOptions struct {
+	Owner            string `json:"owner"`
+	Repo_slug        string `json:"repo_slug"`
+	Uuid             string `json:"uuid"`
+	Cron_pattern     string `json:"cron_pattern"`
+	Ref_name         string `json:"ref_name"` // branch name
+	Selector_type    string `json:"selector_type"`
+	Selector_pattern string `json:"selector_pattern"`
+	Enabled          *bool  `json:"enabled"`
+}
+
+type RepositoryPipelineCacheOptions struct {
+	Owner     string `json:"owner"`
+	Repo_slug string `json:"repo_slug"`
+	Uuid      string `json:"uuid"`
+	Name      string `json:"name"`
+}
+
+type RepositoryPipelineKnownHostOptions struct {
+	Owner     string `json:"owner"`
+	Repo_slug string `json:"repo_slug"`
+	Uuid      string `json:"uuid"`
+	Hostname  string `json:"hostname"`
+	Key_type  string `json:"key_type"`
+	Key       string `json:"key"`
+}
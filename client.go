@@ -12,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/mitchellh/mapstructure"
 	"golang.org/x/net/context"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/bitbucket"
@@ -23,6 +24,8 @@ type Client struct {
 	User         user
 	Teams        teams
 	Repositories *Repositories
+	Projects     *Projects
+	Workspaces   *Workspaces
 	Pagelen      uint64
 }
 
@@ -91,10 +94,17 @@ func injectClient(a *auth) *Client {
 		Diff:               &Diff{c: c},
 		BranchRestrictions: &BranchRestrictions{c: c},
 		Webhooks:           &Webhooks{c: c},
+		Refs:               &Refs{c: c},
+		Permissions:        &Permissions{c: c},
+		DeployKeys:         &DeployKeys{c: c},
+		Pipelines:          &Pipelines{c: c},
+		Deployments:        &Deployments{c: c},
 	}
 	c.Users = &Users{c: c}
 	c.User = &User{c: c}
 	c.Teams = &Teams{c: c}
+	c.Projects = &Projects{c: c}
+	c.Workspaces = &Workspaces{c: c}
 	return c
 }
 
@@ -109,11 +119,7 @@ func (c *Client) executeRaw(method string, urlStr string, text string) ([]byte,
 		return nil, err
 	}
 
-	if c.Auth.user != "" && c.Auth.password != "" {
-		req.SetBasicAuth(c.Auth.user, c.Auth.password)
-	} else if c.Auth.token.Valid() {
-		c.Auth.token.SetAuthHeader(req)
-	}
+	c.authenticate(req)
 
 	client := new(http.Client)
 	resp, err := client.Do(req)
@@ -128,7 +134,7 @@ func (c *Client) executeRaw(method string, urlStr string, text string) ([]byte,
 		return nil, nil
 	}
 
-	if (resp.StatusCode != http.StatusOK) && (resp.StatusCode != http.StatusCreated) {
+	if (resp.StatusCode != http.StatusOK) && (resp.StatusCode != http.StatusCreated) && (resp.StatusCode != http.StatusAccepted) {
 		return nil, fmt.Errorf(resp.Status)
 	}
 
@@ -139,26 +145,28 @@ func (c *Client) executeRaw(method string, urlStr string, text string) ([]byte,
 	return ioutil.ReadAll(resp.Body)
 }
 
+// authenticate sets the basic auth or OAuth token credentials of the client on the request.
+func (c *Client) authenticate(req *http.Request) {
+	if c.Auth.user != "" && c.Auth.password != "" {
+		req.SetBasicAuth(c.Auth.user, c.Auth.password)
+	} else if c.Auth.token.Valid() {
+		c.Auth.token.SetAuthHeader(req)
+	}
+}
+
 func (c *Client) execute(method string, urlStr string, text string) (interface{}, error) {
-	// Use pagination if changed from default value
-	const DEC_RADIX = 10
-	if strings.Contains(urlStr, "/repositories/") {
-		if c.Pagelen != DEFAULT_PAGE_LENGHT {
-			urlObj, err := url.Parse(urlStr)
-			if err != nil {
-				return nil, err
-			}
-			q := urlObj.Query()
-			q.Set("pagelen", strconv.FormatUint(c.Pagelen, DEC_RADIX))
-			urlObj.RawQuery = q.Encode()
-			urlStr = urlObj.String()
-		}
+	urlStr, err := c.withPagelen(urlStr)
+	if err != nil {
+		return nil, err
 	}
 
 	b, err := c.executeRaw(method, urlStr, text)
 	if err != nil {
 		return nil, err
 	}
+	if len(b) == 0 {
+		return nil, nil
+	}
 
 	var result interface{}
 	err = json.Unmarshal(b, &result)
@@ -205,6 +213,52 @@ func (c *Client) execute(method string, urlStr string, text string) (interface{}
 	return result, nil
 }
 
+// decodeValues decodes the "values" of a paginated response into the slice pointed to by values.
+func decodeValues(response interface{}, values interface{}) error {
+	responseMap, ok := response.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("unexpected response of type %T", response)
+	}
+
+	if responseMap["type"] == "error" {
+		return DecodeError(responseMap)
+	}
+
+	return mapstructure.Decode(responseMap["values"], values)
+}
+
+// decodeObject decodes a single resource response into the struct pointed to by object.
+func decodeObject(response interface{}, object interface{}) error {
+	responseMap, ok := response.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("unexpected response of type %T", response)
+	}
+
+	if responseMap["type"] == "error" {
+		return DecodeError(responseMap)
+	}
+
+	return mapstructure.Decode(responseMap, object)
+}
+
+// withPagelen adds the client's page length to repository URLs if it was changed from the default value.
+func (c *Client) withPagelen(urlStr string) (string, error) {
+	const DEC_RADIX = 10
+	if strings.Contains(urlStr, "/repositories/") {
+		if c.Pagelen != DEFAULT_PAGE_LENGHT {
+			urlObj, err := url.Parse(urlStr)
+			if err != nil {
+				return "", err
+			}
+			q := urlObj.Query()
+			q.Set("pagelen", strconv.FormatUint(c.Pagelen, DEC_RADIX))
+			urlObj.RawQuery = q.Encode()
+			urlStr = urlObj.String()
+		}
+	}
+	return urlStr, nil
+}
+
 func (c *Client) requestUrl(template string, args ...interface{}) string {
 
 	if len(args) == 1 && args[0] == "" {
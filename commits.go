@@ -6,6 +6,26 @@ type Commits struct {
 	c *Client
 }
 
+// Commit is a single commit. Commits embedded in other resources, such as the commit of a CommitFile or the
+// Parents of a Commit, usually only carry the Hash, Type and Links.
+type Commit struct {
+	Type    string
+	Hash    string
+	Date    string
+	Message string
+	Author  CommitAuthor
+	Parents []Commit
+	Links   map[string]interface{}
+}
+
+// CommitAuthor is the author of a commit. Raw holds the "Name <email>" string recorded in the commit, User
+// the matching Bitbucket account if there is one.
+type CommitAuthor struct {
+	Type string
+	Raw  string
+	User map[string]interface{}
+}
+
 func (cm *Commits) GetCommits(cmo *CommitsOptions) (interface{}, error) {
 	urlStr := cm.c.requestUrl("/repositories/%s/%s/commits/%s", cmo.Owner, cmo.Repo_slug, cmo.Branchortag)
 	urlStr += cm.buildCommitsQuery(cmo.Include, cmo.Exclude)
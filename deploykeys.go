@@ -0,0 +1,164 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+
+	"github.com/k0kubun/pp"
+	"golang.org/x/crypto/ssh"
+)
+
+type DeployKeys struct {
+	c *Client
+}
+
+// DeployKey is a read-only SSH access key of a repository or a project. Repository is set on repository keys
+// and Project on project keys.
+type DeployKey struct {
+	Type       string
+	Id         int
+	Key        string
+	Label      string
+	Comment    string
+	Added_on   string
+	Last_used  string
+	Owner      Account
+	Repository *Repository
+	Project    *Project
+	Links      map[string]interface{}
+}
+
+// Fingerprint returns the SHA256 fingerprint of the public Key, as printed by ssh-keygen -l.
+func (k *DeployKey) Fingerprint() (string, error) {
+	pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(k.Key))
+	if err != nil {
+		return "", err
+	}
+	return ssh.FingerprintSHA256(pub), nil
+}
+
+// List returns the deploy keys of the repository.
+func (d *DeployKeys) List(do *DeployKeyOptions) ([]*DeployKey, error) {
+	urlStr := d.c.requestUrl("/repositories/%s/%s/deploy-keys", do.Owner, do.Repo_slug)
+	return d.list(urlStr)
+}
+
+// Get returns the deploy key of the repository with the given Id.
+func (d *DeployKeys) Get(do *DeployKeyOptions) (*DeployKey, error) {
+	urlStr := d.c.requestUrl("/repositories/%s/%s/deploy-keys/%d", do.Owner, do.Repo_slug, do.Id)
+	response, err := d.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeDeployKey(response)
+}
+
+// Create adds the public Key, in OpenSSH format, to the repository with an optional Label.
+func (d *DeployKeys) Create(do *DeployKeyOptions) (*DeployKey, error) {
+	data := d.buildDeployKeyBody(do)
+	urlStr := d.c.requestUrl("/repositories/%s/%s/deploy-keys", do.Owner, do.Repo_slug)
+	response, err := d.c.execute(http.MethodPost, urlStr, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeDeployKey(response)
+}
+
+// Update changes the deploy key of the repository with the given Id. Bitbucket requires both Key and Label.
+func (d *DeployKeys) Update(do *DeployKeyOptions) (*DeployKey, error) {
+	data := d.buildDeployKeyBody(do)
+	urlStr := d.c.requestUrl("/repositories/%s/%s/deploy-keys/%d", do.Owner, do.Repo_slug, do.Id)
+	response, err := d.c.execute(http.MethodPut, urlStr, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeDeployKey(response)
+}
+
+// Delete removes the deploy key with the given Id from the repository.
+func (d *DeployKeys) Delete(do *DeployKeyOptions) error {
+	urlStr := d.c.requestUrl("/repositories/%s/%s/deploy-keys/%d", do.Owner, do.Repo_slug, do.Id)
+	_, err := d.c.execute(http.MethodDelete, urlStr, "")
+	return err
+}
+
+// ListForProject returns the deploy keys of the Project, which grant access to all of its repositories.
+func (d *DeployKeys) ListForProject(do *DeployKeyOptions) ([]*DeployKey, error) {
+	urlStr := d.c.requestUrl("/workspaces/%s/projects/%s/deploy-keys", do.Owner, do.Project)
+	return d.list(urlStr)
+}
+
+// GetForProject returns the deploy key of the Project with the given Id.
+func (d *DeployKeys) GetForProject(do *DeployKeyOptions) (*DeployKey, error) {
+	urlStr := d.c.requestUrl("/workspaces/%s/projects/%s/deploy-keys/%d", do.Owner, do.Project, do.Id)
+	response, err := d.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeDeployKey(response)
+}
+
+// CreateForProject adds the public Key to the Project. Project keys cannot be updated.
+func (d *DeployKeys) CreateForProject(do *DeployKeyOptions) (*DeployKey, error) {
+	data := d.buildDeployKeyBody(do)
+	urlStr := d.c.requestUrl("/workspaces/%s/projects/%s/deploy-keys", do.Owner, do.Project)
+	response, err := d.c.execute(http.MethodPost, urlStr, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeDeployKey(response)
+}
+
+// DeleteForProject removes the deploy key with the given Id from the Project.
+func (d *DeployKeys) DeleteForProject(do *DeployKeyOptions) error {
+	urlStr := d.c.requestUrl("/workspaces/%s/projects/%s/deploy-keys/%d", do.Owner, do.Project, do.Id)
+	_, err := d.c.execute(http.MethodDelete, urlStr, "")
+	return err
+}
+
+func (d *DeployKeys) list(urlStr string) ([]*DeployKey, error) {
+	response, err := d.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []*DeployKey
+	if err := decodeValues(response, &keys); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (d *DeployKeys) buildDeployKeyBody(do *DeployKeyOptions) string {
+
+	body := map[string]interface{}{}
+
+	body["key"] = do.Key
+	if do.Label != "" {
+		body["label"] = do.Label
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		pp.Println(err)
+		os.Exit(9)
+	}
+
+	return string(data)
+}
+
+func decodeDeployKey(response interface{}) (*DeployKey, error) {
+	var key = new(DeployKey)
+	if err := decodeObject(response, key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
@@ -0,0 +1,233 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+
+	"github.com/k0kubun/pp"
+)
+
+const (
+	EnvironmentTypeTest       = "Test"
+	EnvironmentTypeStaging    = "Staging"
+	EnvironmentTypeProduction = "Production"
+
+	DeploymentStateUndeployed = "UNDEPLOYED"
+	DeploymentStateInProgress = "IN_PROGRESS"
+	DeploymentStateCompleted  = "COMPLETED"
+)
+
+type Deployments struct {
+	c *Client
+}
+
+// Environment is a deployment environment of a repository.
+type Environment struct {
+	Type                     string
+	Uuid                     string
+	Name                     string
+	Slug                     string
+	Rank                     int
+	Hidden                   bool
+	Environment_type         EnvironmentType
+	Restrictions             EnvironmentRestrictions
+	Deployment_gate_enabled  bool
+	Environment_lock_enabled bool
+}
+
+// EnvironmentType is Test, Staging or Production; Rank orders them.
+type EnvironmentType struct {
+	Type string
+	Name string
+	Rank int
+}
+
+// EnvironmentRestrictions limits who can deploy to an environment.
+type EnvironmentRestrictions struct {
+	Type       string
+	Admin_only bool
+}
+
+// Deployment is a deployment of a release to an environment.
+type Deployment struct {
+	Type        string
+	Uuid        string
+	Number      int
+	Key         string
+	Version     int
+	Environment Environment
+	Release     DeploymentRelease
+	State       DeploymentState
+	Step        PipelineStep
+}
+
+// DeploymentRelease is what gets deployed: the Commit built by the Pipeline run.
+type DeploymentRelease struct {
+	Type       string
+	Uuid       string
+	Name       string
+	Url        string
+	Commit     Commit
+	Pipeline   PipelineRun
+	Created_on string
+}
+
+// DeploymentState is the state of a deployment. Status is set once it is COMPLETED, ie. SUCCESSFUL or FAILED.
+type DeploymentState struct {
+	Type         string
+	Name         string
+	Status       *PipelineStateDetail
+	Url          string
+	Deployer     Account
+	Started_on   string
+	Completed_on string
+}
+
+// IsSuccessful reports whether the deployment completed successfully.
+func (s DeploymentState) IsSuccessful() bool {
+	return s.Name == DeploymentStateCompleted && s.Status != nil && s.Status.Name == PipelineResultSuccessful
+}
+
+// ListEnvironments returns the deployment environments of the repository.
+func (d *Deployments) ListEnvironments(do *DeploymentsOptions) ([]*Environment, error) {
+	urlStr := d.c.requestUrl("/repositories/%s/%s/environments/", do.Owner, do.Repo_slug)
+	response, err := d.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var environments []*Environment
+	if err := decodeValues(response, &environments); err != nil {
+		return nil, err
+	}
+
+	return environments, nil
+}
+
+// GetEnvironment returns the environment with the given Environment UUID.
+func (d *Deployments) GetEnvironment(do *DeploymentsOptions) (*Environment, error) {
+	urlStr := d.c.requestUrl("/repositories/%s/%s/environments/%s", do.Owner, do.Repo_slug, do.Environment)
+	response, err := d.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeEnvironment(response)
+}
+
+// CreateEnvironment creates an environment called Name of the given Environment_type, ie.
+// EnvironmentTypeStaging. Admin_only, when set, restricts deployments to the environment to admins.
+func (d *Deployments) CreateEnvironment(do *DeploymentsOptions) (*Environment, error) {
+	body := map[string]interface{}{
+		"type": "deployment_environment",
+		"name": do.Name,
+		"environment_type": map[string]string{
+			"type": "deployment_environment_type",
+			"name": do.Environment_type,
+		},
+	}
+	if do.Admin_only != nil {
+		body["restrictions"] = map[string]bool{
+			"admin_only": *do.Admin_only,
+		}
+	}
+	urlStr := d.c.requestUrl("/repositories/%s/%s/environments/", do.Owner, do.Repo_slug)
+	response, err := d.c.execute(http.MethodPost, urlStr, d.buildJsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeEnvironment(response)
+}
+
+// UpdateEnvironment renames the environment with the given Environment UUID to Name and sets whether only
+// admins can deploy to it. Bitbucket applies the change asynchronously, so the returned environment may not
+// reflect it yet.
+func (d *Deployments) UpdateEnvironment(do *DeploymentsOptions) (*Environment, error) {
+	change := map[string]interface{}{}
+	if do.Name != "" {
+		change["name"] = do.Name
+	}
+	if do.Admin_only != nil {
+		change["restrictions"] = map[string]bool{
+			"admin_only": *do.Admin_only,
+		}
+	}
+	urlStr := d.c.requestUrl("/repositories/%s/%s/environments/%s/changes/", do.Owner, do.Repo_slug, do.Environment)
+	if _, err := d.c.execute(http.MethodPost, urlStr, d.buildJsonBody(map[string]interface{}{"change": change})); err != nil {
+		return nil, err
+	}
+
+	return d.GetEnvironment(do)
+}
+
+// DeleteEnvironment deletes the environment with the given Environment UUID.
+func (d *Deployments) DeleteEnvironment(do *DeploymentsOptions) error {
+	urlStr := d.c.requestUrl("/repositories/%s/%s/environments/%s", do.Owner, do.Repo_slug, do.Environment)
+	_, err := d.c.execute(http.MethodDelete, urlStr, "")
+	return err
+}
+
+// List returns the deployments of the repository, only those to the given Environment UUID if it is set.
+func (d *Deployments) List(do *DeploymentsOptions) ([]*Deployment, error) {
+	urlStr := d.c.requestUrl("/repositories/%s/%s/deployments/", do.Owner, do.Repo_slug)
+	response, err := d.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var deployments []*Deployment
+	if err := decodeValues(response, &deployments); err != nil {
+		return nil, err
+	}
+
+	if do.Environment == "" {
+		return deployments, nil
+	}
+	// The deployments endpoint has no environment filter.
+	filtered := deployments[:0]
+	for _, deployment := range deployments {
+		if deployment.Environment.Uuid == do.Environment {
+			filtered = append(filtered, deployment)
+		}
+	}
+
+	return filtered, nil
+}
+
+// Get returns the deployment with the given Uuid.
+func (d *Deployments) Get(do *DeploymentsOptions) (*Deployment, error) {
+	urlStr := d.c.requestUrl("/repositories/%s/%s/deployments/%s", do.Owner, do.Repo_slug, do.Uuid)
+	response, err := d.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var deployment = new(Deployment)
+	if err := decodeObject(response, deployment); err != nil {
+		return nil, err
+	}
+
+	return deployment, nil
+}
+
+func (d *Deployments) buildJsonBody(body map[string]interface{}) string {
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		pp.Println(err)
+		os.Exit(9)
+	}
+
+	return string(data)
+}
+
+func decodeEnvironment(response interface{}) (*Environment, error) {
+	var environment = new(Environment)
+	if err := decodeObject(response, environment); err != nil {
+		return nil, err
+	}
+
+	return environment, nil
+}
@@ -1,15 +1,146 @@
 package bitbucket
 
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	DiffstatAdded    = "added"
+	DiffstatRemoved  = "removed"
+	DiffstatModified = "modified"
+	DiffstatRenamed  = "renamed"
+)
+
 type Diff struct {
 	c *Client
 }
 
-func (d *Diff) GetDiff(do *DiffOptions) (interface{}, error) {
+// Diffstat is the change summary of a single file. Old is nil for added files and New is nil for removed ones.
+type Diffstat struct {
+	Type          string
+	Status        string
+	Lines_added   int
+	Lines_removed int
+	Old           *CommitFile
+	New           *CommitFile
+}
+
+// OldPath returns the path of the file before the change, or "" if it was added.
+func (ds *Diffstat) OldPath() string {
+	if ds.Old == nil {
+		return ""
+	}
+	return ds.Old.Path
+}
+
+// NewPath returns the path of the file after the change, or "" if it was removed.
+func (ds *Diffstat) NewPath() string {
+	if ds.New == nil {
+		return ""
+	}
+	return ds.New.Path
+}
+
+// GetDiff returns the raw unified diff for the given spec. The endpoint responds with text/plain, so the
+// bytes are passed through untouched; use ParseDiff or GetDiffFiles to get at the individual files and hunks.
+// The optional DiffOptions fields select paths, context lines, whitespace handling, binary and rename
+// detection and whether to diff against the merge base.
+func (d *Diff) GetDiff(do *DiffOptions) ([]byte, error) {
 	urlStr := d.c.requestUrl("/repositories/%s/%s/diff/%s", do.Owner, do.Repo_slug, do.Spec)
-	return d.c.execute("GET", urlStr, "")
+	urlStr += buildDiffQuery(do)
+	return d.c.executeRaw("GET", urlStr, "")
 }
 
-func (d *Diff) GetPatch(do *DiffOptions) (interface{}, error) {
+// GetDiffFiles fetches the diff for the given spec and parses it into files, hunks and lines.
+func (d *Diff) GetDiffFiles(do *DiffOptions) ([]*DiffFile, error) {
+	raw, err := d.GetDiff(do)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseDiff(raw)
+}
+
+// GetPatch returns the raw patch series for the given spec, in the mbox format of git format-patch.
+func (d *Diff) GetPatch(do *DiffOptions) ([]byte, error) {
 	urlStr := d.c.requestUrl("/repositories/%s/%s/patch/%s", do.Owner, do.Repo_slug, do.Spec)
-	return d.c.execute("GET", urlStr, "")
+	urlStr += buildDiffQuery(do)
+	return d.c.executeRaw("GET", urlStr, "")
+}
+
+// GetPatchCommits fetches the patch series for the given spec and parses it into one PatchCommit per commit.
+func (d *Diff) GetPatchCommits(do *DiffOptions) ([]*PatchCommit, error) {
+	raw, err := d.GetPatch(do)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParsePatch(raw)
+}
+
+// GetDiffstat returns the per file change summary for the given spec. The Path, Ignore_whitespace, Renames
+// and Topic fields of the DiffOptions are honoured.
+func (d *Diff) GetDiffstat(do *DiffOptions) ([]*Diffstat, error) {
+	urlStr := d.c.requestUrl("/repositories/%s/%s/diffstat/%s", do.Owner, do.Repo_slug, do.Spec)
+	urlStr += buildDiffQuery(do)
+	response, err := d.c.execute("GET", urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeDiffstats(response)
+}
+
+// buildDiffQuery turns the optional DiffOptions fields into the query string shared by the diff, patch and
+// diffstat endpoints. Fields left unset are omitted so the Bitbucket defaults apply.
+func buildDiffQuery(do *DiffOptions) string {
+
+	p := url.Values{}
+
+	for _, path := range do.Path {
+		p.Add("path", path)
+	}
+	if do.Context != nil {
+		p.Add("context", strconv.Itoa(*do.Context))
+	}
+	if do.Ignore_whitespace {
+		p.Add("ignore_whitespace", "true")
+	}
+	if do.Binary != nil {
+		p.Add("binary", strconv.FormatBool(*do.Binary))
+	}
+	if do.Renames != nil {
+		p.Add("renames", strconv.FormatBool(*do.Renames))
+	}
+	if do.Topic != nil {
+		p.Add("topic", strconv.FormatBool(*do.Topic))
+	}
+
+	if len(p) == 0 {
+		return ""
+	}
+	return "?" + p.Encode()
+}
+
+func decodeDiffstats(response interface{}) ([]*Diffstat, error) {
+	responseMap, ok := response.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected response of type %T", response)
+	}
+
+	if responseMap["type"] == "error" {
+		return nil, DecodeError(responseMap)
+	}
+
+	var diffstats []*Diffstat
+	err := mapstructure.Decode(responseMap["values"], &diffstats)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffstats, nil
 }
@@ -0,0 +1,375 @@
+package bitbucket
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DiffLineType tells how a line of a hunk relates to the old and the new version of a file.
+type DiffLineType int
+
+const (
+	DiffLineContext DiffLineType = iota
+	DiffLineAdded
+	DiffLineRemoved
+)
+
+func (t DiffLineType) String() string {
+	switch t {
+	case DiffLineAdded:
+		return "added"
+	case DiffLineRemoved:
+		return "removed"
+	default:
+		return "context"
+	}
+}
+
+// DiffLine is a single line of a hunk. OldLine is 0 for added lines and NewLine is 0 for removed lines.
+type DiffLine struct {
+	Type           DiffLineType
+	Content        string
+	OldLine        int
+	NewLine        int
+	NoNewlineAtEOF bool
+}
+
+// DiffHunk is one "@@ -a,b +c,d @@" block of a file diff. Section holds the text git prints after the
+// closing "@@", usually the enclosing function.
+type DiffHunk struct {
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	Section  string
+	Lines    []*DiffLine
+}
+
+// DiffFile is the diff of a single file. OldPath is empty for added files and NewPath is empty for
+// removed ones. The modes are only set when git reports them, ie. for new or deleted files and mode changes.
+type DiffFile struct {
+	OldPath    string
+	NewPath    string
+	OldMode    string
+	NewMode    string
+	OldIndex   string
+	NewIndex   string
+	IsNew      bool
+	IsDeleted  bool
+	IsRename   bool
+	IsCopy     bool
+	IsBinary   bool
+	Similarity int
+	Hunks      []*DiffHunk
+}
+
+// Path returns the path the file is known by after the change, or its old path when it was removed.
+func (f *DiffFile) Path() string {
+	if f.IsDeleted {
+		return f.OldPath
+	}
+	return f.NewPath
+}
+
+// Status summarises the change as one of added, removed, renamed, copied or modified.
+func (f *DiffFile) Status() string {
+	switch {
+	case f.IsNew:
+		return "added"
+	case f.IsDeleted:
+		return "removed"
+	case f.IsRename:
+		return "renamed"
+	case f.IsCopy:
+		return "copied"
+	default:
+		return "modified"
+	}
+}
+
+// ModeChanged reports whether the file mode changed without the file being added or removed.
+func (f *DiffFile) ModeChanged() bool {
+	return !f.IsNew && !f.IsDeleted && f.OldMode != "" && f.NewMode != "" && f.OldMode != f.NewMode
+}
+
+// Stats returns the number of added and removed lines over all hunks.
+func (f *DiffFile) Stats() (added, removed int) {
+	for _, h := range f.Hunks {
+		for _, l := range h.Lines {
+			switch l.Type {
+			case DiffLineAdded:
+				added++
+			case DiffLineRemoved:
+				removed++
+			}
+		}
+	}
+	return added, removed
+}
+
+var hunkHeaderRegexp = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@ ?(.*)$`)
+
+// ParseDiff parses git style unified diff output, as returned by the diff endpoints, into one DiffFile
+// per file. Anything before the first "diff --git" line is ignored, as are combined (merge) diffs.
+func ParseDiff(data []byte) ([]*DiffFile, error) {
+	lines := strings.Split(string(data), "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	var files []*DiffFile
+	var cur *DiffFile
+	skipBinary := false
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSuffix(lines[i], "\r")
+
+		if strings.HasPrefix(line, "diff --git ") {
+			cur = &DiffFile{}
+			cur.OldPath, cur.NewPath = parseDiffGitHeader(strings.TrimPrefix(line, "diff --git "))
+			files = append(files, cur)
+			skipBinary = false
+			continue
+		}
+		if strings.HasPrefix(line, "diff --cc ") || strings.HasPrefix(line, "diff --combined ") {
+			cur = nil
+			continue
+		}
+		if cur == nil || skipBinary {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "old mode "):
+			cur.OldMode = strings.TrimPrefix(line, "old mode ")
+		case strings.HasPrefix(line, "new mode "):
+			cur.NewMode = strings.TrimPrefix(line, "new mode ")
+		case strings.HasPrefix(line, "deleted file mode "):
+			cur.IsDeleted = true
+			cur.OldMode = strings.TrimPrefix(line, "deleted file mode ")
+		case strings.HasPrefix(line, "new file mode "):
+			cur.IsNew = true
+			cur.NewMode = strings.TrimPrefix(line, "new file mode ")
+		case strings.HasPrefix(line, "similarity index "):
+			cur.Similarity, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(line, "similarity index "), "%"))
+		case strings.HasPrefix(line, "rename from "):
+			cur.IsRename = true
+			cur.OldPath = unquoteDiffPath(strings.TrimPrefix(line, "rename from "))
+		case strings.HasPrefix(line, "rename to "):
+			cur.IsRename = true
+			cur.NewPath = unquoteDiffPath(strings.TrimPrefix(line, "rename to "))
+		case strings.HasPrefix(line, "copy from "):
+			cur.IsCopy = true
+			cur.OldPath = unquoteDiffPath(strings.TrimPrefix(line, "copy from "))
+		case strings.HasPrefix(line, "copy to "):
+			cur.IsCopy = true
+			cur.NewPath = unquoteDiffPath(strings.TrimPrefix(line, "copy to "))
+		case strings.HasPrefix(line, "index "):
+			parseDiffIndex(cur, strings.TrimPrefix(line, "index "))
+		case strings.HasPrefix(line, "--- "):
+			if p := parseDiffFilePath(strings.TrimPrefix(line, "--- "), "a/"); p != "" {
+				cur.OldPath = p
+			} else {
+				cur.IsNew = true
+			}
+		case strings.HasPrefix(line, "+++ "):
+			if p := parseDiffFilePath(strings.TrimPrefix(line, "+++ "), "b/"); p != "" {
+				cur.NewPath = p
+			} else {
+				cur.IsDeleted = true
+			}
+		case strings.HasPrefix(line, "Binary files "):
+			cur.IsBinary = true
+		case line == "GIT binary patch":
+			cur.IsBinary = true
+			skipBinary = true
+		case strings.HasPrefix(line, "@@ "):
+			hunk, next, err := parseDiffHunk(lines, i)
+			if err != nil {
+				return nil, err
+			}
+			cur.Hunks = append(cur.Hunks, hunk)
+			i = next - 1
+		}
+	}
+
+	for _, f := range files {
+		if f.IsNew {
+			f.OldPath = ""
+		}
+		if f.IsDeleted {
+			f.NewPath = ""
+		}
+	}
+
+	return files, nil
+}
+
+// parseDiffHunk parses the hunk whose header is at lines[start] and returns it together with the index
+// of the first line after it.
+func parseDiffHunk(lines []string, start int) (*DiffHunk, int, error) {
+	header := strings.TrimSuffix(lines[start], "\r")
+	m := hunkHeaderRegexp.FindStringSubmatch(header)
+	if m == nil {
+		return nil, 0, fmt.Errorf("diff: line %d: malformed hunk header %q", start+1, header)
+	}
+
+	hunk := &DiffHunk{
+		OldStart: atoiDefault(m[1], 0),
+		OldLines: atoiDefault(m[2], 1),
+		NewStart: atoiDefault(m[3], 0),
+		NewLines: atoiDefault(m[4], 1),
+		Section:  m[5],
+	}
+
+	oldLine, newLine := hunk.OldStart, hunk.NewStart
+	oldLeft, newLeft := hunk.OldLines, hunk.NewLines
+
+	i := start + 1
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if oldLeft <= 0 && newLeft <= 0 {
+			// A "\ No newline at end of file" marker may still follow the last line.
+			if !strings.HasPrefix(line, `\`) {
+				break
+			}
+		}
+
+		if line == "" {
+			// Some tools strip the single space of empty context lines.
+			line = " "
+		}
+
+		switch line[0] {
+		case ' ':
+			hunk.Lines = append(hunk.Lines, &DiffLine{Type: DiffLineContext, Content: line[1:], OldLine: oldLine, NewLine: newLine})
+			oldLine++
+			newLine++
+			oldLeft--
+			newLeft--
+		case '-':
+			hunk.Lines = append(hunk.Lines, &DiffLine{Type: DiffLineRemoved, Content: line[1:], OldLine: oldLine})
+			oldLine++
+			oldLeft--
+		case '+':
+			hunk.Lines = append(hunk.Lines, &DiffLine{Type: DiffLineAdded, Content: line[1:], NewLine: newLine})
+			newLine++
+			newLeft--
+		case '\\':
+			if n := len(hunk.Lines); n > 0 {
+				hunk.Lines[n-1].NoNewlineAtEOF = true
+			}
+		default:
+			return nil, 0, fmt.Errorf("diff: line %d: unexpected %q in hunk", i+1, line)
+		}
+
+		if oldLeft < 0 || newLeft < 0 {
+			return nil, 0, fmt.Errorf("diff: line %d: hunk is longer than its header %q", i+1, header)
+		}
+	}
+
+	if oldLeft > 0 || newLeft > 0 {
+		return nil, 0, fmt.Errorf("diff: hunk %q at line %d is truncated", header, start+1)
+	}
+
+	return hunk, i, nil
+}
+
+// parseDiffGitHeader splits the "a/old b/new" part of a "diff --git" line. Unquoted paths containing
+// spaces are ambiguous; the names are guessed here and later replaced by the ---/+++ or rename lines.
+func parseDiffGitHeader(s string) (string, string) {
+	if strings.HasPrefix(s, `"`) {
+		if end := closingQuote(s); end > 0 {
+			return parseDiffFilePath(s[:end+1], "a/"), parseDiffFilePath(strings.TrimSpace(s[end+1:]), "b/")
+		}
+	}
+	if strings.HasSuffix(s, `"`) {
+		if idx := strings.LastIndex(s, ` "`); idx >= 0 {
+			return parseDiffFilePath(s[:idx], "a/"), parseDiffFilePath(s[idx+1:], "b/")
+		}
+	}
+
+	// Unchanged names make the header symmetric: "a/<name> b/<name>".
+	if n := len(s); n%2 == 1 {
+		left, right := s[:n/2], s[n/2+1:]
+		if strings.HasPrefix(left, "a/") && strings.HasPrefix(right, "b/") && left[2:] == right[2:] {
+			return left[2:], right[2:]
+		}
+	}
+	if idx := strings.LastIndex(s, " b/"); idx >= 0 {
+		return strings.TrimPrefix(s[:idx], "a/"), s[idx+3:]
+	}
+
+	return s, s
+}
+
+// parseDiffFilePath returns the path of a ---/+++ line without its a/ or b/ prefix, or "" for /dev/null.
+func parseDiffFilePath(s, prefix string) string {
+	if strings.HasPrefix(s, `"`) {
+		s = unquoteDiffPath(s)
+	} else if idx := strings.IndexByte(s, '\t'); idx >= 0 {
+		s = s[:idx]
+	}
+	if s == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(s, prefix)
+}
+
+// unquoteDiffPath undoes git's C style quoting of paths with special characters.
+func unquoteDiffPath(s string) string {
+	if !strings.HasPrefix(s, `"`) {
+		return s
+	}
+	if end := closingQuote(s); end > 0 {
+		s = s[:end+1]
+	}
+	if u, err := strconv.Unquote(s); err == nil {
+		return u
+	}
+	return strings.Trim(s, `"`)
+}
+
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// parseDiffIndex handles "index <old>..<new> [<mode>]".
+func parseDiffIndex(f *DiffFile, s string) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return
+	}
+	if hashes := strings.SplitN(fields[0], "..", 2); len(hashes) == 2 {
+		f.OldIndex, f.NewIndex = hashes[0], hashes[1]
+	}
+	if len(fields) > 1 {
+		if f.OldMode == "" {
+			f.OldMode = fields[1]
+		}
+		if f.NewMode == "" {
+			f.NewMode = fields[1]
+		}
+	}
+}
+
+func atoiDefault(s string, def int) int {
+	if s == "" {
+		return def
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return def
+	}
+	return n
+}
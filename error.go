@@ -1,14 +1,22 @@
 package bitbucket
 
 import (
+	"encoding/json"
 	"errors"
+	"io/ioutil"
+	"net/http"
 
 	"github.com/mitchellh/mapstructure"
 )
 
 type BitbucketError struct {
-	Message string
-	Fields  map[string][]string
+	Message    string
+	Fields     map[string][]string
+	StatusCode int
+}
+
+func (e *BitbucketError) Error() string {
+	return e.Message
 }
 
 func DecodeError(e map[string]interface{}) error {
@@ -20,3 +28,30 @@ func DecodeError(e map[string]interface{}) error {
 
 	return errors.New(bitbucketError.Message)
 }
+
+// decodeErrorResponse turns a failed response into a *BitbucketError, using the error message and fields of
+// the body when Bitbucket sent them and the HTTP status otherwise.
+func decodeErrorResponse(resp *http.Response) error {
+	bitbucketError := &BitbucketError{Message: resp.Status, StatusCode: resp.StatusCode}
+	if resp.Body == nil {
+		return bitbucketError
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return bitbucketError
+	}
+
+	var body map[string]interface{}
+	if json.Unmarshal(b, &body) != nil || body["type"] != "error" {
+		return bitbucketError
+	}
+	if err := mapstructure.Decode(body["error"], bitbucketError); err != nil {
+		return err
+	}
+	if bitbucketError.Message == "" {
+		bitbucketError.Message = resp.Status
+	}
+
+	return bitbucketError
+}
@@ -0,0 +1,273 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+
+	"github.com/k0kubun/pp"
+)
+
+// Email is an email address of the authenticated user.
+type Email struct {
+	Type         string
+	Email        string
+	Is_primary   bool
+	Is_confirmed bool
+	Links        map[string]interface{}
+}
+
+// SSHKey is an SSH key of a user account.
+type SSHKey struct {
+	Type       string
+	Uuid       string
+	Key        string
+	Comment    string
+	Label      string
+	Created_on string
+	Last_used  string
+	Owner      Account
+	Links      map[string]interface{}
+}
+
+// GPGKey is a GPG key of a user account. Subkeys carry the Parent_fingerprint of their primary key.
+type GPGKey struct {
+	Type               string
+	Key                string
+	Key_id             string
+	Fingerprint        string
+	Parent_fingerprint string
+	Name               string
+	Comment            string
+	Created_on         string
+	Added_on           string
+	Expires_on         string
+	Last_used          string
+	Subkeys            []GPGKey
+	Owner              Account
+	Links              map[string]interface{}
+}
+
+// ListEmails returns the email addresses of the authenticated user.
+func (u *User) ListEmails() ([]*Email, error) {
+	response, err := u.Emails()
+	if err != nil {
+		return nil, err
+	}
+
+	var emails []*Email
+	if err := decodeValues(response, &emails); err != nil {
+		return nil, err
+	}
+
+	return emails, nil
+}
+
+// GetEmail returns the given email address of the authenticated user.
+func (u *User) GetEmail(email string) (*Email, error) {
+	urlStr := u.c.requestUrl("/user/emails/%s", email)
+	response, err := u.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var e = new(Email)
+	if err := decodeObject(response, e); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// ListSSHKeys returns the SSH keys of the Owner, or of the authenticated user if Owner is empty.
+func (u *User) ListSSHKeys(uo *UserKeyOptions) ([]*SSHKey, error) {
+	owner, err := u.keysOwner(uo)
+	if err != nil {
+		return nil, err
+	}
+	urlStr := u.c.requestUrl("/users/%s/ssh-keys", owner)
+	response, err := u.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []*SSHKey
+	if err := decodeValues(response, &keys); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// GetSSHKey returns the SSH key with the given Uuid.
+func (u *User) GetSSHKey(uo *UserKeyOptions) (*SSHKey, error) {
+	owner, err := u.keysOwner(uo)
+	if err != nil {
+		return nil, err
+	}
+	urlStr := u.c.requestUrl("/users/%s/ssh-keys/%s", owner, uo.Uuid)
+	response, err := u.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeSSHKey(response)
+}
+
+// AddSSHKey adds the public Key, in OpenSSH format, with an optional Label.
+func (u *User) AddSSHKey(uo *UserKeyOptions) (*SSHKey, error) {
+	owner, err := u.keysOwner(uo)
+	if err != nil {
+		return nil, err
+	}
+	data := u.buildKeyBody(uo)
+	urlStr := u.c.requestUrl("/users/%s/ssh-keys", owner)
+	response, err := u.c.execute(http.MethodPost, urlStr, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeSSHKey(response)
+}
+
+// UpdateSSHKey changes the Label of the SSH key with the given Uuid. The key itself cannot be changed.
+func (u *User) UpdateSSHKey(uo *UserKeyOptions) (*SSHKey, error) {
+	owner, err := u.keysOwner(uo)
+	if err != nil {
+		return nil, err
+	}
+	data := u.buildKeyBody(&UserKeyOptions{Label: uo.Label})
+	urlStr := u.c.requestUrl("/users/%s/ssh-keys/%s", owner, uo.Uuid)
+	response, err := u.c.execute(http.MethodPut, urlStr, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeSSHKey(response)
+}
+
+// DeleteSSHKey deletes the SSH key with the given Uuid.
+func (u *User) DeleteSSHKey(uo *UserKeyOptions) error {
+	owner, err := u.keysOwner(uo)
+	if err != nil {
+		return err
+	}
+	urlStr := u.c.requestUrl("/users/%s/ssh-keys/%s", owner, uo.Uuid)
+	_, err = u.c.execute(http.MethodDelete, urlStr, "")
+	return err
+}
+
+// ListGPGKeys returns the GPG keys of the Owner, or of the authenticated user if Owner is empty.
+func (u *User) ListGPGKeys(uo *UserKeyOptions) ([]*GPGKey, error) {
+	owner, err := u.keysOwner(uo)
+	if err != nil {
+		return nil, err
+	}
+	urlStr := u.c.requestUrl("/users/%s/gpg-keys", owner)
+	response, err := u.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []*GPGKey
+	if err := decodeValues(response, &keys); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// GetGPGKey returns the GPG key with the given Fingerprint.
+func (u *User) GetGPGKey(uo *UserKeyOptions) (*GPGKey, error) {
+	owner, err := u.keysOwner(uo)
+	if err != nil {
+		return nil, err
+	}
+	urlStr := u.c.requestUrl("/users/%s/gpg-keys/%s", owner, uo.Fingerprint)
+	response, err := u.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeGPGKey(response)
+}
+
+// AddGPGKey adds the ASCII armored public Key. Bitbucket has no update for GPG keys; delete and add it again.
+func (u *User) AddGPGKey(uo *UserKeyOptions) (*GPGKey, error) {
+	owner, err := u.keysOwner(uo)
+	if err != nil {
+		return nil, err
+	}
+	data := u.buildKeyBody(&UserKeyOptions{Key: uo.Key})
+	urlStr := u.c.requestUrl("/users/%s/gpg-keys", owner)
+	response, err := u.c.execute(http.MethodPost, urlStr, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeGPGKey(response)
+}
+
+// DeleteGPGKey deletes the GPG key with the given Fingerprint, along with its subkeys.
+func (u *User) DeleteGPGKey(uo *UserKeyOptions) error {
+	owner, err := u.keysOwner(uo)
+	if err != nil {
+		return err
+	}
+	urlStr := u.c.requestUrl("/users/%s/gpg-keys/%s", owner, uo.Fingerprint)
+	_, err = u.c.execute(http.MethodDelete, urlStr, "")
+	return err
+}
+
+// keysOwner returns the account the keys belong to. The key endpoints have no /user form, so the UUID of the
+// authenticated user is looked up when no Owner is given.
+func (u *User) keysOwner(uo *UserKeyOptions) (string, error) {
+	if uo.Owner != "" {
+		return uo.Owner, nil
+	}
+
+	profile, err := u.GetProfile()
+	if err != nil {
+		return "", err
+	}
+
+	return profile.Uuid, nil
+}
+
+func (u *User) buildKeyBody(uo *UserKeyOptions) string {
+
+	body := map[string]interface{}{}
+
+	if uo.Key != "" {
+		body["key"] = uo.Key
+	}
+	if uo.Label != "" {
+		body["label"] = uo.Label
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		pp.Println(err)
+		os.Exit(9)
+	}
+
+	return string(data)
+}
+
+func decodeSSHKey(response interface{}) (*SSHKey, error) {
+	var key = new(SSHKey)
+	if err := decodeObject(response, key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func decodeGPGKey(response interface{}) (*GPGKey, error) {
+	var key = new(GPGKey)
+	if err := decodeObject(response, key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
@@ -0,0 +1,139 @@
+package bitbucket
+
+import (
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// PatchCommit is one commit of a patch series. Files holds the parsed diff of the commit and Diff the
+// raw diff text it was parsed from.
+type PatchCommit struct {
+	Hash        string
+	AuthorName  string
+	AuthorEmail string
+	Date        time.Time
+	Subject     string
+	Body        string
+	Diff        string
+	Files       []*DiffFile
+}
+
+var (
+	mboxSeparatorRegexp = regexp.MustCompile(`^From ([0-9a-f]{40}) `)
+	patchSubjectRegexp  = regexp.MustCompile(`^\[PATCH[^\]]*\]\s*`)
+)
+
+// ParsePatch parses git format-patch output, one or more mbox style messages, as returned by the patch
+// endpoints. Input without a leading "From <hash>" line is treated as a single message.
+func ParsePatch(data []byte) ([]*PatchCommit, error) {
+	var chunks []string
+	var hashes []string
+	var cur strings.Builder
+	started := false
+
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		if m := mboxSeparatorRegexp.FindStringSubmatch(line); m != nil {
+			if started {
+				chunks = append(chunks, cur.String())
+				cur.Reset()
+			}
+			hashes = append(hashes, m[1])
+			started = true
+			continue
+		}
+		if !started {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			hashes = append(hashes, "")
+			started = true
+		}
+		cur.WriteString(line)
+	}
+	if started {
+		chunks = append(chunks, cur.String())
+	}
+
+	commits := make([]*PatchCommit, 0, len(chunks))
+	for i, chunk := range chunks {
+		commit, err := parsePatchMessage(chunk)
+		if err != nil {
+			return nil, fmt.Errorf("patch %d: %s", i+1, err)
+		}
+		commit.Hash = hashes[i]
+		commits = append(commits, commit)
+	}
+
+	return commits, nil
+}
+
+func parsePatchMessage(chunk string) (*PatchCommit, error) {
+	msg, err := mail.ReadMessage(strings.NewReader(chunk))
+	if err != nil {
+		return nil, err
+	}
+
+	commit := &PatchCommit{}
+	decoder := new(mime.WordDecoder)
+
+	if from := msg.Header.Get("From"); from != "" {
+		if addr, err := mail.ParseAddress(from); err == nil {
+			commit.AuthorName, commit.AuthorEmail = addr.Name, addr.Address
+		} else if name, err := decoder.DecodeHeader(from); err == nil {
+			commit.AuthorName = name
+		}
+	}
+	if date, err := msg.Header.Date(); err == nil {
+		commit.Date = date
+	}
+	subject, err := decoder.DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		subject = msg.Header.Get("Subject")
+	}
+	commit.Subject = patchSubjectRegexp.ReplaceAllString(subject, "")
+
+	b, err := ioutil.ReadAll(msg.Body)
+	if err != nil {
+		return nil, err
+	}
+	body := string(b)
+
+	// The commit message ends at the "---" line that precedes the diffstat, or at the diff itself
+	// when the patch was generated without a diffstat.
+	// A commit without a body starts with that line, so check for it first: the diff after it may remove
+	// a "--" line, which also reads as "\n---\n".
+	message, diff := body, ""
+	if strings.HasPrefix(body, "---\n") {
+		message, diff = "", body[len("---\n"):]
+	} else if idx := strings.Index(body, "\n---\n"); idx >= 0 {
+		message = body[:idx+1]
+		diff = body[idx+len("\n---\n"):]
+	}
+	if idx := strings.Index(diff, "diff --git "); idx >= 0 {
+		diff = diff[idx:]
+	} else if idx := strings.Index(message, "diff --git "); idx >= 0 {
+		message, diff = message[:idx], message[idx:]
+	} else {
+		diff = ""
+	}
+
+	// Drop the "-- \n<git version>" signature format-patch appends.
+	if idx := strings.LastIndex(diff, "\n-- \n"); idx >= 0 {
+		diff = diff[:idx+1]
+	}
+
+	commit.Body = strings.TrimSpace(message)
+	commit.Diff = diff
+
+	commit.Files, err = ParseDiff([]byte(diff))
+	if err != nil {
+		return nil, err
+	}
+
+	return commit, nil
+}
@@ -0,0 +1,195 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/k0kubun/pp"
+)
+
+const (
+	PermissionRead  = "read"
+	PermissionWrite = "write"
+	PermissionAdmin = "admin"
+)
+
+type Permissions struct {
+	c *Client
+}
+
+// Group is a user group of a workspace.
+type Group struct {
+	Type      string
+	Name      string
+	Slug      string
+	Full_slug string
+	Owner     Account
+	Workspace Workspace
+	Links     map[string]interface{}
+}
+
+// RepositoryUserPermission is the permission explicitly granted to a user on a repository.
+type RepositoryUserPermission struct {
+	Type       string
+	Permission string
+	User       Account
+	Repository Repository
+	Links      map[string]interface{}
+}
+
+// RepositoryGroupPermission is the permission granted to a group on a repository.
+type RepositoryGroupPermission struct {
+	Type       string
+	Permission string
+	Group      Group
+	Repository Repository
+	Links      map[string]interface{}
+}
+
+// RepositoryPermission is the effective permission of a user on a repository, from any source.
+type RepositoryPermission struct {
+	Type       string
+	Permission string
+	User       Account
+	Repository Repository
+}
+
+// ListUsers returns the users with an explicit permission on the repository.
+func (p *Permissions) ListUsers(po *RepositoryPermissionsOptions) ([]*RepositoryUserPermission, error) {
+	urlStr := p.c.requestUrl("/repositories/%s/%s/permissions-config/users", po.Owner, po.Repo_slug)
+	response, err := p.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var permissions []*RepositoryUserPermission
+	if err := decodeValues(response, &permissions); err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+// GetUser returns the explicit permission of the User, given by account id or UUID, on the repository.
+func (p *Permissions) GetUser(po *RepositoryPermissionsOptions) (*RepositoryUserPermission, error) {
+	urlStr := p.c.requestUrl("/repositories/%s/%s/permissions-config/users/%s", po.Owner, po.Repo_slug, po.User)
+	response, err := p.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var permission = new(RepositoryUserPermission)
+	if err := decodeObject(response, permission); err != nil {
+		return nil, err
+	}
+
+	return permission, nil
+}
+
+// UpdateUser grants the User the given Permission on the repository, replacing any permission it had.
+func (p *Permissions) UpdateUser(po *RepositoryPermissionsOptions) (*RepositoryUserPermission, error) {
+	data, err := p.buildPermissionBody(po)
+	if err != nil {
+		return nil, err
+	}
+	urlStr := p.c.requestUrl("/repositories/%s/%s/permissions-config/users/%s", po.Owner, po.Repo_slug, po.User)
+	response, err := p.c.execute(http.MethodPut, urlStr, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var permission = new(RepositoryUserPermission)
+	if err := decodeObject(response, permission); err != nil {
+		return nil, err
+	}
+
+	return permission, nil
+}
+
+// DeleteUser revokes the explicit permission of the User on the repository.
+func (p *Permissions) DeleteUser(po *RepositoryPermissionsOptions) error {
+	urlStr := p.c.requestUrl("/repositories/%s/%s/permissions-config/users/%s", po.Owner, po.Repo_slug, po.User)
+	_, err := p.c.execute(http.MethodDelete, urlStr, "")
+	return err
+}
+
+// ListGroups returns the groups with a permission on the repository.
+func (p *Permissions) ListGroups(po *RepositoryPermissionsOptions) ([]*RepositoryGroupPermission, error) {
+	urlStr := p.c.requestUrl("/repositories/%s/%s/permissions-config/groups", po.Owner, po.Repo_slug)
+	response, err := p.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var permissions []*RepositoryGroupPermission
+	if err := decodeValues(response, &permissions); err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+// GetGroup returns the permission of the Group, given by slug, on the repository.
+func (p *Permissions) GetGroup(po *RepositoryPermissionsOptions) (*RepositoryGroupPermission, error) {
+	urlStr := p.c.requestUrl("/repositories/%s/%s/permissions-config/groups/%s", po.Owner, po.Repo_slug, po.Group)
+	response, err := p.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var permission = new(RepositoryGroupPermission)
+	if err := decodeObject(response, permission); err != nil {
+		return nil, err
+	}
+
+	return permission, nil
+}
+
+// UpdateGroup grants the Group the given Permission on the repository, replacing any permission it had.
+func (p *Permissions) UpdateGroup(po *RepositoryPermissionsOptions) (*RepositoryGroupPermission, error) {
+	data, err := p.buildPermissionBody(po)
+	if err != nil {
+		return nil, err
+	}
+	urlStr := p.c.requestUrl("/repositories/%s/%s/permissions-config/groups/%s", po.Owner, po.Repo_slug, po.Group)
+	response, err := p.c.execute(http.MethodPut, urlStr, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var permission = new(RepositoryGroupPermission)
+	if err := decodeObject(response, permission); err != nil {
+		return nil, err
+	}
+
+	return permission, nil
+}
+
+// DeleteGroup revokes the permission of the Group on the repository.
+func (p *Permissions) DeleteGroup(po *RepositoryPermissionsOptions) error {
+	urlStr := p.c.requestUrl("/repositories/%s/%s/permissions-config/groups/%s", po.Owner, po.Repo_slug, po.Group)
+	_, err := p.c.execute(http.MethodDelete, urlStr, "")
+	return err
+}
+
+func (p *Permissions) buildPermissionBody(po *RepositoryPermissionsOptions) (string, error) {
+	switch po.Permission {
+	case PermissionRead, PermissionWrite, PermissionAdmin:
+	default:
+		return "", fmt.Errorf("unknown permission %q", po.Permission)
+	}
+
+	body := map[string]interface{}{}
+
+	body["permission"] = po.Permission
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		pp.Println(err)
+		os.Exit(9)
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,279 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/k0kubun/pp"
+)
+
+const (
+	PipelineStatePending    = "PENDING"
+	PipelineStateInProgress = "IN_PROGRESS"
+	PipelineStatePaused     = "PAUSED"
+	PipelineStateCompleted  = "COMPLETED"
+
+	PipelineResultSuccessful = "SUCCESSFUL"
+	PipelineResultFailed     = "FAILED"
+	PipelineResultError      = "ERROR"
+	PipelineResultStopped    = "STOPPED"
+	PipelineResultExpired    = "EXPIRED"
+)
+
+// DefaultWaitInterval is how often Wait polls a run when no positive interval is given.
+const DefaultWaitInterval = 5 * time.Second
+
+type Pipelines struct {
+	c *Client
+}
+
+// PipelineRun is a run of a pipeline. Pipeline is the Pipelines configuration of a repository.
+type PipelineRun struct {
+	Type                string
+	Uuid                string
+	Build_number        int
+	Run_number          int
+	Creator             Account
+	Repository          Repository
+	Target              PipelineTarget
+	Trigger             PipelineTrigger
+	State               PipelineState
+	Variables           []PipelineVariable
+	Created_on          string
+	Completed_on        string
+	Duration_in_seconds int
+	Build_seconds_used  int
+	Links               map[string]interface{}
+}
+
+// PipelineTarget is what a pipeline runs on. Ref_type and Ref_name are empty for runs on a bare commit.
+type PipelineTarget struct {
+	Type     string
+	Ref_type string
+	Ref_name string
+	Commit   Commit
+	Selector PipelineSelector
+}
+
+// PipelineSelector picks the pipeline of bitbucket-pipelines.yml to run, ie. type custom with the name of a
+// custom pipeline as Pattern.
+type PipelineSelector struct {
+	Type    string
+	Pattern string
+}
+
+type PipelineTrigger struct {
+	Type string
+	Name string
+}
+
+// PipelineState is the state of a run or a step. Stage is set while IN_PROGRESS and Result once COMPLETED.
+type PipelineState struct {
+	Type   string
+	Name   string
+	Stage  *PipelineStateDetail
+	Result *PipelineStateDetail
+}
+
+type PipelineStateDetail struct {
+	Type string
+	Name string
+}
+
+// IsCompleted reports whether the run has finished, whatever its result.
+func (s PipelineState) IsCompleted() bool {
+	return s.Name == PipelineStateCompleted
+}
+
+// ResultName returns the name of the result, ie. PipelineResultSuccessful, or an empty string if the run has
+// not completed.
+func (s PipelineState) ResultName() string {
+	if s.Result == nil {
+		return ""
+	}
+	return s.Result.Name
+}
+
+// Trigger starts a pipeline. Ref_type and Ref_name run it on the head of a branch or tag, Commit on a given
+// commit; Selector_pattern picks a custom pipeline.
+func (p *Pipelines) Trigger(po *PipelinesOptions) (*PipelineRun, error) {
+	data := p.buildTriggerBody(po)
+	urlStr := p.c.requestUrl("/repositories/%s/%s/pipelines/", po.Owner, po.Repo_slug)
+	response, err := p.c.execute(http.MethodPost, urlStr, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodePipelineRun(response)
+}
+
+// List returns the runs of the repository, filtered by the target fields and Status, and sorted by Sort,
+// ie. -created_on.
+func (p *Pipelines) List(po *PipelinesOptions) ([]*PipelineRun, error) {
+	urlStr := p.c.requestUrl("/repositories/%s/%s/pipelines/", po.Owner, po.Repo_slug)
+	urlStr += p.buildPipelinesQuery(po)
+	response, err := p.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []*PipelineRun
+	if err := decodeValues(response, &runs); err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+// Get returns the run with the given Uuid.
+func (p *Pipelines) Get(po *PipelinesOptions) (*PipelineRun, error) {
+	urlStr := p.c.requestUrl("/repositories/%s/%s/pipelines/%s", po.Owner, po.Repo_slug, po.Uuid)
+	response, err := p.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodePipelineRun(response)
+}
+
+// Stop stops the run with the given Uuid. The run completes with the STOPPED result shortly after.
+func (p *Pipelines) Stop(po *PipelinesOptions) error {
+	urlStr := p.c.requestUrl("/repositories/%s/%s/pipelines/%s/stopPipeline", po.Owner, po.Repo_slug, po.Uuid)
+	_, err := p.c.execute(http.MethodPost, urlStr, "")
+	return err
+}
+
+// Wait polls the run with the given Uuid every interval until it completes, and returns the completed run.
+// A non-positive interval polls every DefaultWaitInterval. A zero timeout waits for as long as the run takes;
+// otherwise the run is polled one last time at the deadline before giving up.
+func (p *Pipelines) Wait(po *PipelinesOptions, interval, timeout time.Duration) (*PipelineRun, error) {
+	if interval <= 0 {
+		interval = DefaultWaitInterval
+	}
+
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+
+	for {
+		run, err := p.Get(po)
+		if err != nil {
+			return nil, err
+		}
+		if run.State.IsCompleted() {
+			return run, nil
+		}
+
+		sleep := interval
+		if !deadline.IsZero() {
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				return run, fmt.Errorf("pipeline %s still %s after %s", po.Uuid, run.State.Name, timeout)
+			}
+			if remaining < sleep {
+				sleep = remaining
+			}
+		}
+		time.Sleep(sleep)
+	}
+}
+
+func (p *Pipelines) buildPipelinesQuery(po *PipelinesOptions) string {
+
+	q := url.Values{}
+
+	if po.Ref_type != "" {
+		q.Add("target.ref_type", po.Ref_type)
+	}
+	if po.Ref_name != "" {
+		q.Add("target.ref_name", po.Ref_name)
+	}
+	if po.Commit != "" {
+		q.Add("target.commit.hash", po.Commit)
+	}
+	if po.Selector_type != "" {
+		q.Add("target.selector.type", po.Selector_type)
+	}
+	if po.Selector_pattern != "" {
+		q.Add("target.selector.pattern", po.Selector_pattern)
+	}
+	if po.Status != "" {
+		q.Add("status", po.Status)
+	}
+	if po.Sort != "" {
+		q.Add("sort", po.Sort)
+	}
+
+	if len(q) == 0 {
+		return ""
+	}
+	return "?" + q.Encode()
+}
+
+func (p *Pipelines) buildTriggerBody(po *PipelinesOptions) string {
+
+	body := map[string]interface{}{}
+
+	target := map[string]interface{}{}
+	if po.Ref_name != "" {
+		target["type"] = "pipeline_ref_target"
+		target["ref_name"] = po.Ref_name
+		target["ref_type"] = "branch"
+		if po.Ref_type != "" {
+			target["ref_type"] = po.Ref_type
+		}
+	} else {
+		target["type"] = "pipeline_commit_target"
+	}
+	if po.Commit != "" {
+		target["commit"] = map[string]string{
+			"type": "commit",
+			"hash": po.Commit,
+		}
+	}
+	if po.Selector_pattern != "" {
+		selector := map[string]string{
+			"type":    "custom",
+			"pattern": po.Selector_pattern,
+		}
+		if po.Selector_type != "" {
+			selector["type"] = po.Selector_type
+		}
+		target["selector"] = selector
+	}
+	body["target"] = target
+
+	if len(po.Variables) > 0 {
+		variables := make([]map[string]interface{}, len(po.Variables))
+		for i, v := range po.Variables {
+			variables[i] = map[string]interface{}{
+				"key":     v.Key,
+				"value":   v.Value,
+				"secured": v.Secured,
+			}
+		}
+		body["variables"] = variables
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		pp.Println(err)
+		os.Exit(9)
+	}
+
+	return string(data)
+}
+
+func decodePipelineRun(response interface{}) (*PipelineRun, error) {
+	var run = new(PipelineRun)
+	if err := decodeObject(response, run); err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
@@ -0,0 +1,349 @@
+package bitbucket
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	KeyTypeEd25519 = "ed25519"
+	KeyTypeRSA     = "rsa"
+)
+
+// PipelineSchedule runs the pipeline picked by the Target selector on a cron schedule.
+type PipelineSchedule struct {
+	Type         string
+	Uuid         string
+	Enabled      bool
+	Cron_pattern string
+	Target       PipelineTarget
+	Created_on   string
+	Updated_on   string
+}
+
+// PipelineCache is a dependency cache saved by a pipeline step.
+type PipelineCache struct {
+	Type            string
+	Uuid            string
+	Name            string
+	Path            string
+	Key_hash        string
+	Pipeline_uuid   string
+	Step_uuid       string
+	File_size_bytes int
+	Created_on      string
+}
+
+// PipelineKnownHost is an SSH host that pipeline steps trust.
+type PipelineKnownHost struct {
+	Type       string
+	Uuid       string
+	Hostname   string
+	Public_key PipelineSSHPublicKey
+}
+
+type PipelineSSHPublicKey struct {
+	Type               string
+	Key_type           string
+	Key                string
+	Md5_fingerprint    string
+	Sha256_fingerprint string
+}
+
+// ListPipelineSchedules returns the pipeline schedules of the repository.
+func (r *Repository) ListPipelineSchedules(rpso *RepositoryPipelineScheduleOptions) ([]*PipelineSchedule, error) {
+	urlStr := r.c.requestUrl("/repositories/%s/%s/pipelines_config/schedules/", rpso.Owner, rpso.Repo_slug)
+	response, err := r.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var schedules []*PipelineSchedule
+	if err := decodeValues(response, &schedules); err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+// GetPipelineSchedule returns the pipeline schedule with the given Uuid.
+func (r *Repository) GetPipelineSchedule(rpso *RepositoryPipelineScheduleOptions) (*PipelineSchedule, error) {
+	urlStr := r.c.requestUrl("/repositories/%s/%s/pipelines_config/schedules/%s", rpso.Owner, rpso.Repo_slug, rpso.Uuid)
+	response, err := r.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodePipelineSchedule(response)
+}
+
+// AddPipelineSchedule schedules the pipeline of the Ref_name branch, or the custom pipeline given by
+// Selector_pattern, with a Quartz Cron_pattern, ie. "0 0 2 * * ? *". Schedules are enabled unless Enabled says
+// otherwise.
+func (r *Repository) AddPipelineSchedule(rpso *RepositoryPipelineScheduleOptions) (*PipelineSchedule, error) {
+	data := r.buildPipelineScheduleBody(rpso)
+	urlStr := r.c.requestUrl("/repositories/%s/%s/pipelines_config/schedules/", rpso.Owner, rpso.Repo_slug)
+	response, err := r.c.execute(http.MethodPost, urlStr, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodePipelineSchedule(response)
+}
+
+// UpdatePipelineSchedule enables or disables the pipeline schedule with the given Uuid. Bitbucket does not allow
+// changing the cron pattern or target of a schedule; delete and add it again instead.
+func (r *Repository) UpdatePipelineSchedule(rpso *RepositoryPipelineScheduleOptions) (*PipelineSchedule, error) {
+	body := map[string]interface{}{}
+	if rpso.Enabled != nil {
+		body["enabled"] = *rpso.Enabled
+	}
+	data := r.buildJsonBody(body)
+	urlStr := r.c.requestUrl("/repositories/%s/%s/pipelines_config/schedules/%s", rpso.Owner, rpso.Repo_slug, rpso.Uuid)
+	response, err := r.c.execute(http.MethodPut, urlStr, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodePipelineSchedule(response)
+}
+
+// DeletePipelineSchedule deletes the pipeline schedule with the given Uuid.
+func (r *Repository) DeletePipelineSchedule(rpso *RepositoryPipelineScheduleOptions) error {
+	urlStr := r.c.requestUrl("/repositories/%s/%s/pipelines_config/schedules/%s", rpso.Owner, rpso.Repo_slug, rpso.Uuid)
+	_, err := r.c.execute(http.MethodDelete, urlStr, "")
+	return err
+}
+
+// ListPipelineCaches returns the pipeline caches of the repository.
+func (r *Repository) ListPipelineCaches(rpco *RepositoryPipelineCacheOptions) ([]*PipelineCache, error) {
+	urlStr := r.c.requestUrl("/repositories/%s/%s/pipelines-config/caches", rpco.Owner, rpco.Repo_slug)
+	response, err := r.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var caches []*PipelineCache
+	if err := decodeValues(response, &caches); err != nil {
+		return nil, err
+	}
+
+	return caches, nil
+}
+
+// DeletePipelineCache deletes the pipeline cache with the given Uuid, or all the caches called Name if Uuid is
+// empty.
+func (r *Repository) DeletePipelineCache(rpco *RepositoryPipelineCacheOptions) error {
+	urlStr := r.c.requestUrl("/repositories/%s/%s/pipelines-config/caches", rpco.Owner, rpco.Repo_slug)
+	if rpco.Uuid != "" {
+		urlStr += "/" + rpco.Uuid
+	} else {
+		urlStr += "?" + url.Values{"name": {rpco.Name}}.Encode()
+	}
+	_, err := r.c.execute(http.MethodDelete, urlStr, "")
+	return err
+}
+
+// ListPipelineKnownHosts returns the SSH known hosts of the repository pipelines.
+func (r *Repository) ListPipelineKnownHosts(rpkho *RepositoryPipelineKnownHostOptions) ([]*PipelineKnownHost, error) {
+	urlStr := r.c.requestUrl("/repositories/%s/%s/pipelines_config/ssh/known_hosts/", rpkho.Owner, rpkho.Repo_slug)
+	response, err := r.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var hosts []*PipelineKnownHost
+	if err := decodeValues(response, &hosts); err != nil {
+		return nil, err
+	}
+
+	return hosts, nil
+}
+
+// GetPipelineKnownHost returns the known host with the given Uuid.
+func (r *Repository) GetPipelineKnownHost(rpkho *RepositoryPipelineKnownHostOptions) (*PipelineKnownHost, error) {
+	urlStr := r.c.requestUrl("/repositories/%s/%s/pipelines_config/ssh/known_hosts/%s", rpkho.Owner, rpkho.Repo_slug, rpkho.Uuid)
+	response, err := r.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodePipelineKnownHost(response)
+}
+
+// AddPipelineKnownHost trusts the Hostname, with an optional port as in "example.com:2222", with the public Key
+// of type Key_type, ie. ssh-ed25519.
+func (r *Repository) AddPipelineKnownHost(rpkho *RepositoryPipelineKnownHostOptions) (*PipelineKnownHost, error) {
+	data := r.buildPipelineKnownHostBody(rpkho)
+	urlStr := r.c.requestUrl("/repositories/%s/%s/pipelines_config/ssh/known_hosts/", rpkho.Owner, rpkho.Repo_slug)
+	response, err := r.c.execute(http.MethodPost, urlStr, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodePipelineKnownHost(response)
+}
+
+// UpdatePipelineKnownHost replaces the hostname and public key of the known host with the given Uuid.
+func (r *Repository) UpdatePipelineKnownHost(rpkho *RepositoryPipelineKnownHostOptions) (*PipelineKnownHost, error) {
+	data := r.buildPipelineKnownHostBody(rpkho)
+	urlStr := r.c.requestUrl("/repositories/%s/%s/pipelines_config/ssh/known_hosts/%s", rpkho.Owner, rpkho.Repo_slug, rpkho.Uuid)
+	response, err := r.c.execute(http.MethodPut, urlStr, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodePipelineKnownHost(response)
+}
+
+// DeletePipelineKnownHost deletes the known host with the given Uuid.
+func (r *Repository) DeletePipelineKnownHost(rpkho *RepositoryPipelineKnownHostOptions) error {
+	urlStr := r.c.requestUrl("/repositories/%s/%s/pipelines_config/ssh/known_hosts/%s", rpkho.Owner, rpkho.Repo_slug, rpkho.Uuid)
+	_, err := r.c.execute(http.MethodDelete, urlStr, "")
+	return err
+}
+
+// GetPipelineKeyPair returns the SSH key pair of the repository pipelines. Only the public key is ever
+// returned.
+func (r *Repository) GetPipelineKeyPair(rpkpo *RepositoryPipelineKeyPairOptions) (*PipelineKeyPair, error) {
+	urlStr := r.c.requestUrl("/repositories/%s/%s/pipelines_config/ssh/key_pair", rpkpo.Owner, rpkpo.Repo_slug)
+	response, err := r.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodePipelineKeyPairRepository(response)
+}
+
+// DeletePipelineKeyPair removes the SSH key pair of the repository pipelines.
+func (r *Repository) DeletePipelineKeyPair(rpkpo *RepositoryPipelineKeyPairOptions) error {
+	urlStr := r.c.requestUrl("/repositories/%s/%s/pipelines_config/ssh/key_pair", rpkpo.Owner, rpkpo.Repo_slug)
+	_, err := r.c.execute(http.MethodDelete, urlStr, "")
+	return err
+}
+
+// GeneratePipelineKeyPair generates a key pair of the given type, KeyTypeEd25519 or KeyTypeRSA, and installs
+// it as the SSH key pair of the repository pipelines, replacing any previous one. The private key never leaves
+// the request; the returned key pair holds the public key to add to the target hosts.
+func (r *Repository) GeneratePipelineKeyPair(rpkpo *RepositoryPipelineKeyPairOptions, keyType string) (*PipelineKeyPair, error) {
+	privateKey, publicKey, err := generateKeyPair(keyType)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.AddPipelineKeyPair(&RepositoryPipelineKeyPairOptions{
+		Owner:       rpkpo.Owner,
+		Repo_slug:   rpkpo.Repo_slug,
+		Private_key: privateKey,
+		Public_key:  publicKey,
+	})
+}
+
+// generateKeyPair returns a PEM encoded private key without passphrase and its public key in authorized_keys
+// format.
+func generateKeyPair(keyType string) (string, string, error) {
+	var signer interface{}
+	var block *pem.Block
+
+	switch keyType {
+	case KeyTypeEd25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return "", "", err
+		}
+		block, err = ssh.MarshalPrivateKey(key, "")
+		if err != nil {
+			return "", "", err
+		}
+		signer = key
+	case KeyTypeRSA:
+		key, err := rsa.GenerateKey(rand.Reader, 4096)
+		if err != nil {
+			return "", "", err
+		}
+		block = &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+		signer = key
+	default:
+		return "", "", fmt.Errorf("unknown key type %q", keyType)
+	}
+
+	sshSigner, err := ssh.NewSignerFromKey(signer)
+	if err != nil {
+		return "", "", err
+	}
+	publicKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshSigner.PublicKey())))
+
+	return string(pem.EncodeToMemory(block)), publicKey, nil
+}
+
+func (r *Repository) buildPipelineScheduleBody(rpso *RepositoryPipelineScheduleOptions) string {
+
+	body := map[string]interface{}{}
+
+	selector := map[string]string{
+		"type":    "branches",
+		"pattern": rpso.Ref_name,
+	}
+	if rpso.Selector_pattern != "" {
+		selector["type"] = "custom"
+		selector["pattern"] = rpso.Selector_pattern
+	}
+	if rpso.Selector_type != "" {
+		selector["type"] = rpso.Selector_type
+	}
+
+	body["type"] = "pipeline_schedule"
+	body["target"] = map[string]interface{}{
+		"type":     "pipeline_ref_target",
+		"ref_type": "branch",
+		"ref_name": rpso.Ref_name,
+		"selector": selector,
+	}
+	body["cron_pattern"] = rpso.Cron_pattern
+	body["enabled"] = true
+	if rpso.Enabled != nil {
+		body["enabled"] = *rpso.Enabled
+	}
+
+	return r.buildJsonBody(body)
+}
+
+func (r *Repository) buildPipelineKnownHostBody(rpkho *RepositoryPipelineKnownHostOptions) string {
+
+	body := map[string]interface{}{}
+
+	body["hostname"] = rpkho.Hostname
+	body["public_key"] = map[string]string{
+		"key_type": rpkho.Key_type,
+		"key":      rpkho.Key,
+	}
+
+	return r.buildJsonBody(body)
+}
+
+func decodePipelineSchedule(response interface{}) (*PipelineSchedule, error) {
+	var schedule = new(PipelineSchedule)
+	if err := decodeObject(response, schedule); err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+func decodePipelineKnownHost(response interface{}) (*PipelineKnownHost, error) {
+	var host = new(PipelineKnownHost)
+	if err := decodeObject(response, host); err != nil {
+		return nil, err
+	}
+
+	return host, nil
+}
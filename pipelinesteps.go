@@ -0,0 +1,202 @@
+package bitbucket
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// PipelineStep is a step of a pipeline run.
+type PipelineStep struct {
+	Type                string
+	Uuid                string
+	Name                string
+	Run_number          int
+	State               PipelineState
+	Image               PipelineImage
+	Setup_commands      []PipelineCommand
+	Script_commands     []PipelineCommand
+	Teardown_commands   []PipelineCommand
+	Started_on          string
+	Completed_on        string
+	Duration_in_seconds int
+	Build_seconds_used  int
+	Max_time            int
+}
+
+// PipelineImage is the Docker image a step runs in.
+type PipelineImage struct {
+	Name string
+}
+
+type PipelineCommand struct {
+	Name    string
+	Command string
+}
+
+// PipelineTestReport summarizes the test results a step reported.
+type PipelineTestReport struct {
+	Type                            string
+	Number_of_test_cases            int
+	Number_of_successful_test_cases int
+	Number_of_failed_test_cases     int
+	Number_of_error_test_cases      int
+	Number_of_skipped_test_cases    int
+}
+
+// PipelineTestCase is a test case of a step test report. Duration is an ISO 8601 duration, ie. PT0.5S.
+type PipelineTestCase struct {
+	Type                 string
+	Uuid                 string
+	Name                 string
+	Fully_qualified_name string
+	Package_name         string
+	Status               string
+	Duration             string
+}
+
+// PipelineTestCaseReason is the failure output of a test case.
+type PipelineTestCaseReason struct {
+	Type        string
+	Message     string
+	Stack_trace string
+	Output      string
+}
+
+// Duration returns how long the step ran.
+func (s *PipelineStep) Duration() time.Duration {
+	return time.Duration(s.Duration_in_seconds) * time.Second
+}
+
+// ListSteps returns the steps of the run with the given Uuid.
+func (p *Pipelines) ListSteps(po *PipelinesOptions) ([]*PipelineStep, error) {
+	urlStr := p.c.requestUrl("/repositories/%s/%s/pipelines/%s/steps/", po.Owner, po.Repo_slug, po.Uuid)
+	response, err := p.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var steps []*PipelineStep
+	if err := decodeValues(response, &steps); err != nil {
+		return nil, err
+	}
+
+	return steps, nil
+}
+
+// GetStep returns the step with the given Step_uuid.
+func (p *Pipelines) GetStep(po *PipelinesOptions) (*PipelineStep, error) {
+	urlStr := p.c.requestUrl("/repositories/%s/%s/pipelines/%s/steps/%s", po.Owner, po.Repo_slug, po.Uuid, po.Step_uuid)
+	response, err := p.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var step = new(PipelineStep)
+	if err := decodeObject(response, step); err != nil {
+		return nil, err
+	}
+
+	return step, nil
+}
+
+// GetStepLog returns the log of the step with the given Step_uuid from the byte offset on. A running step is
+// tailed by calling it again with the offset moved past the returned bytes; an empty log means nothing new.
+func (p *Pipelines) GetStepLog(po *PipelinesOptions, offset int64) ([]byte, error) {
+	log, err := p.StepLogReader(po, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer log.Close()
+
+	return ioutil.ReadAll(log)
+}
+
+// StepLogReader streams the log of the step with the given Step_uuid from the byte offset on, using an HTTP
+// Range request. The caller must close the reader.
+func (p *Pipelines) StepLogReader(po *PipelinesOptions, offset int64) (io.ReadCloser, error) {
+	urlStr := p.c.requestUrl("/repositories/%s/%s/pipelines/%s/steps/%s/log", po.Owner, po.Repo_slug, po.Uuid, po.Step_uuid)
+	req, err := http.NewRequest(http.MethodGet, urlStr, nil)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	p.c.authenticate(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		return resp.Body, nil
+	case http.StatusOK:
+		// The whole log was sent, skip what the caller already has.
+		if _, err := io.CopyN(ioutil.Discard, resp.Body, offset); err != nil && err != io.EOF {
+			resp.Body.Close()
+			return nil, err
+		}
+		return resp.Body, nil
+	case http.StatusRequestedRangeNotSatisfiable:
+		resp.Body.Close()
+		return ioutil.NopCloser(strings.NewReader("")), nil
+	default:
+		defer resp.Body.Close()
+		return nil, decodeErrorResponse(resp)
+	}
+}
+
+// GetTestReport returns the test report summary of the step with the given Step_uuid.
+func (p *Pipelines) GetTestReport(po *PipelinesOptions) (*PipelineTestReport, error) {
+	urlStr := p.c.requestUrl("/repositories/%s/%s/pipelines/%s/steps/%s/test_reports", po.Owner, po.Repo_slug, po.Uuid, po.Step_uuid)
+	response, err := p.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var report = new(PipelineTestReport)
+	if err := decodeObject(response, report); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// ListTestCases returns the test cases of the step test report.
+func (p *Pipelines) ListTestCases(po *PipelinesOptions) ([]*PipelineTestCase, error) {
+	urlStr := p.c.requestUrl("/repositories/%s/%s/pipelines/%s/steps/%s/test_reports/test_cases", po.Owner, po.Repo_slug, po.Uuid, po.Step_uuid)
+	response, err := p.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var testCases []*PipelineTestCase
+	if err := decodeValues(response, &testCases); err != nil {
+		return nil, err
+	}
+
+	return testCases, nil
+}
+
+// ListTestCaseReasons returns the failure output of the test case with the given Test_case_uuid.
+func (p *Pipelines) ListTestCaseReasons(po *PipelinesOptions) ([]*PipelineTestCaseReason, error) {
+	urlStr := p.c.requestUrl("/repositories/%s/%s/pipelines/%s/steps/%s/test_reports/test_cases/%s/test_case_reasons",
+		po.Owner, po.Repo_slug, po.Uuid, po.Step_uuid, po.Test_case_uuid)
+	response, err := p.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var reasons []*PipelineTestCaseReason
+	if err := decodeValues(response, &reasons); err != nil {
+		return nil, err
+	}
+
+	return reasons, nil
+}
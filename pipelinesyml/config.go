@@ -0,0 +1,285 @@
+// Package pipelinesyml parses and validates bitbucket-pipelines.yml, the Bitbucket Pipelines configuration
+// file of a repository.
+package pipelinesyml
+
+import (
+	"github.com/ktrysmt/go-bitbucket"
+	"gopkg.in/yaml.v2"
+)
+
+// ConfigFile is the path of the Pipelines configuration in a repository.
+const ConfigFile = "bitbucket-pipelines.yml"
+
+// Config is a parsed bitbucket-pipelines.yml. YAML anchors, aliases and merge keys are resolved while parsing,
+// so steps shared through definitions appear in full wherever they are used.
+type Config struct {
+	Image       *Image       `yaml:"image"`
+	Clone       *Clone       `yaml:"clone"`
+	Options     *Options     `yaml:"options"`
+	Definitions *Definitions `yaml:"definitions"`
+	Pipelines   *Pipelines   `yaml:"pipelines"`
+}
+
+// Image is a Docker image, given either as a name or with registry credentials.
+type Image struct {
+	Name      string          `yaml:"name"`
+	Username  string          `yaml:"username"`
+	Password  string          `yaml:"password"`
+	Email     string          `yaml:"email"`
+	RunAsUser int             `yaml:"run-as-user"`
+	AWS       *AWSCredentials `yaml:"aws"`
+}
+
+type AWSCredentials struct {
+	AccessKey string `yaml:"access-key"`
+	SecretKey string `yaml:"secret-key"`
+	OIDCRole  string `yaml:"oidc-role"`
+}
+
+// Clone configures how the repository is cloned. Depth is a number of commits or "full".
+type Clone struct {
+	Depth         string `yaml:"depth"`
+	LFS           bool   `yaml:"lfs"`
+	Enabled       *bool  `yaml:"enabled"`
+	SkipSSLVerify bool   `yaml:"skip-ssl-verify"`
+}
+
+// Options are the global defaults of all steps.
+type Options struct {
+	MaxTime int    `yaml:"max-time"`
+	Size    string `yaml:"size"`
+	Docker  bool   `yaml:"docker"`
+}
+
+// Definitions holds the custom caches and services, and the steps shared through YAML anchors.
+type Definitions struct {
+	Caches   map[string]Cache   `yaml:"caches"`
+	Services map[string]Service `yaml:"services"`
+	Steps    []Item             `yaml:"steps"`
+}
+
+// Cache is a custom cache, given either as a path or with the files its key is computed from.
+type Cache struct {
+	Path string    `yaml:"path"`
+	Key  *CacheKey `yaml:"key"`
+}
+
+type CacheKey struct {
+	Files []string `yaml:"files"`
+}
+
+// Service is a container running next to the steps that use it. Memory is in megabytes.
+type Service struct {
+	Image     *Image            `yaml:"image"`
+	Memory    int               `yaml:"memory"`
+	Type      string            `yaml:"type"`
+	Variables map[string]string `yaml:"variables"`
+}
+
+// Pipelines holds the pipelines by trigger. Branches, Tags, Bookmarks and PullRequests are keyed by glob
+// pattern and Custom by name.
+type Pipelines struct {
+	Default      []Item            `yaml:"default"`
+	Branches     map[string][]Item `yaml:"branches"`
+	Tags         map[string][]Item `yaml:"tags"`
+	Bookmarks    map[string][]Item `yaml:"bookmarks"`
+	PullRequests map[string][]Item `yaml:"pull-requests"`
+	Custom       map[string][]Item `yaml:"custom"`
+}
+
+// Item is an entry of a pipeline. Exactly one of its fields is set; Variables only appears as the first item of
+// a custom pipeline.
+type Item struct {
+	Step      *Step      `yaml:"step"`
+	Parallel  *Parallel  `yaml:"parallel"`
+	Stage     *Stage     `yaml:"stage"`
+	Variables []Variable `yaml:"variables"`
+}
+
+// Parallel is a group of steps run at the same time.
+type Parallel struct {
+	FailFast bool   `yaml:"fail-fast"`
+	Steps    []Item `yaml:"steps"`
+}
+
+// Stage is a group of steps run one after the other and deployed together.
+type Stage struct {
+	Name       string     `yaml:"name"`
+	Deployment string     `yaml:"deployment"`
+	Trigger    string     `yaml:"trigger"`
+	Condition  *Condition `yaml:"condition"`
+	Steps      []Item     `yaml:"steps"`
+}
+
+type Step struct {
+	Name        string     `yaml:"name"`
+	Image       *Image     `yaml:"image"`
+	Script      []Script   `yaml:"script"`
+	AfterScript []Script   `yaml:"after-script"`
+	Caches      []string   `yaml:"caches"`
+	Services    []string   `yaml:"services"`
+	Artifacts   *Artifacts `yaml:"artifacts"`
+	Trigger     string     `yaml:"trigger"`
+	Deployment  string     `yaml:"deployment"`
+	Size        string     `yaml:"size"`
+	MaxTime     int        `yaml:"max-time"`
+	Clone       *Clone     `yaml:"clone"`
+	Condition   *Condition `yaml:"condition"`
+	OIDC        bool       `yaml:"oidc"`
+	RunsOn      StringList `yaml:"runs-on"`
+	FailFast    bool       `yaml:"fail-fast"`
+}
+
+// Script is a line of a step script: a shell Command, or a Pipe with its variables.
+type Script struct {
+	Command   string                 `yaml:"-"`
+	Pipe      string                 `yaml:"pipe"`
+	Variables map[string]interface{} `yaml:"variables"`
+}
+
+// Artifacts are the files passed on to the next steps, given either as a list of paths or with Download.
+type Artifacts struct {
+	Download *bool    `yaml:"download"`
+	Paths    []string `yaml:"paths"`
+}
+
+// Condition restricts a step or stage to the changesets touching the given paths.
+type Condition struct {
+	Changesets struct {
+		IncludePaths []string `yaml:"includePaths"`
+		ExcludePaths []string `yaml:"excludePaths"`
+	} `yaml:"changesets"`
+}
+
+// Variable is a variable prompted for when running a custom pipeline.
+type Variable struct {
+	Name          string   `yaml:"name"`
+	Default       string   `yaml:"default"`
+	AllowedValues []string `yaml:"allowed-values"`
+	Description   string   `yaml:"description"`
+}
+
+// StringList is a list of strings that may also be written as a single string.
+type StringList []string
+
+// Parse parses the content of a bitbucket-pipelines.yml.
+func Parse(data []byte) (*Config, error) {
+	var config = new(Config)
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// Load reads and parses the bitbucket-pipelines.yml of the repository at the given commit hash or branch, or at
+// the head of the main branch if revision is empty.
+func Load(r *bitbucket.Repository, ro *bitbucket.RepositoryOptions, revision string) (*Config, error) {
+	data, err := r.GetFile(ro, ConfigFile, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(data)
+}
+
+// All returns the pipelines keyed by their path in the file, ie. "default" or "branches.main".
+func (p *Pipelines) All() map[string][]Item {
+	all := map[string][]Item{}
+	if p == nil {
+		return all
+	}
+
+	if p.Default != nil {
+		all["default"] = p.Default
+	}
+	for section, pipelines := range map[string]map[string][]Item{
+		"branches":      p.Branches,
+		"tags":          p.Tags,
+		"bookmarks":     p.Bookmarks,
+		"pull-requests": p.PullRequests,
+		"custom":        p.Custom,
+	} {
+		for name, items := range pipelines {
+			all[section+"."+name] = items
+		}
+	}
+
+	return all
+}
+
+// Steps returns the steps of the item, in order, looking into parallel groups and stages.
+func (i *Item) Steps() []*Step {
+	switch {
+	case i.Step != nil:
+		return []*Step{i.Step}
+	case i.Parallel != nil:
+		return stepsOf(i.Parallel.Steps)
+	case i.Stage != nil:
+		return stepsOf(i.Stage.Steps)
+	}
+	return nil
+}
+
+func stepsOf(items []Item) []*Step {
+	var steps []*Step
+	for i := range items {
+		steps = append(steps, items[i].Steps()...)
+	}
+	return steps
+}
+
+func (i *Image) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&i.Name); err == nil {
+		return nil
+	}
+
+	type plain Image
+	return unmarshal((*plain)(i))
+}
+
+func (c *Cache) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&c.Path); err == nil {
+		return nil
+	}
+
+	type plain Cache
+	return unmarshal((*plain)(c))
+}
+
+func (p *Parallel) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&p.Steps); err == nil {
+		return nil
+	}
+
+	type plain Parallel
+	return unmarshal((*plain)(p))
+}
+
+func (s *Script) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&s.Command); err == nil {
+		return nil
+	}
+
+	type plain Script
+	return unmarshal((*plain)(s))
+}
+
+func (a *Artifacts) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&a.Paths); err == nil {
+		return nil
+	}
+
+	type plain Artifacts
+	return unmarshal((*plain)(a))
+}
+
+func (l *StringList) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err == nil {
+		*l = StringList{s}
+		return nil
+	}
+
+	return unmarshal((*[]string)(l))
+}
@@ -0,0 +1,199 @@
+package pipelinesyml
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// predefinedCaches are the caches Bitbucket provides without a definition.
+var predefinedCaches = map[string]bool{
+	"composer":   true,
+	"dotnetcore": true,
+	"gradle":     true,
+	"ivy2":       true,
+	"maven":      true,
+	"node":       true,
+	"pip":        true,
+	"sbt":        true,
+	"docker":     true,
+}
+
+var validSizes = map[string]bool{"1x": true, "2x": true, "4x": true, "8x": true, "16x": true}
+
+// ValidationError is a mistake found by Validate. Path locates it in the file, ie. "branches.main[1].step".
+type ValidationError struct {
+	Path    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// Validate checks the configuration for the common mistakes Bitbucket rejects when a pipeline starts, without
+// contacting Bitbucket. It returns nil if it found none.
+func (c *Config) Validate() []error {
+	v := &validator{config: c}
+
+	if c.Options != nil && c.Options.Size != "" && !validSizes[c.Options.Size] {
+		v.errorf("options", "unknown size %q", c.Options.Size)
+	}
+
+	all := c.Pipelines.All()
+	if len(all) == 0 {
+		v.errorf("pipelines", "no pipeline is defined")
+	}
+
+	names := make([]string, 0, len(all))
+	for name := range all {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		v.validatePipeline(name, all[name])
+	}
+
+	return v.errs
+}
+
+type validator struct {
+	config      *Config
+	errs        []error
+	deployments map[string]string
+}
+
+func (v *validator) errorf(path, format string, args ...interface{}) {
+	v.errs = append(v.errs, &ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) validatePipeline(name string, items []Item) {
+	v.deployments = map[string]string{}
+
+	if len(items) == 0 {
+		v.errorf(name, "pipeline has no steps")
+	}
+
+	for i, item := range items {
+		path := fmt.Sprintf("%s[%d]", name, i)
+		if item.Variables != nil {
+			if i != 0 || !strings.HasPrefix(name, "custom.") {
+				v.errorf(path, "variables are only allowed as the first item of a custom pipeline")
+			}
+			continue
+		}
+		v.validateItem(path, item)
+
+		if i == 0 && item.Step != nil && item.Step.Trigger == "manual" {
+			v.errorf(path+".step", "the first step of a pipeline cannot be manual")
+		}
+	}
+}
+
+func (v *validator) validateItem(path string, item Item) {
+	set := 0
+	for _, isSet := range []bool{item.Step != nil, item.Parallel != nil, item.Stage != nil, item.Variables != nil} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		v.errorf(path, "item must have exactly one of step, parallel or stage")
+		return
+	}
+
+	switch {
+	case item.Step != nil:
+		v.validateStep(path+".step", item.Step)
+	case item.Parallel != nil:
+		v.validateParallel(path+".parallel", item.Parallel)
+	case item.Stage != nil:
+		v.validateStage(path+".stage", item.Stage)
+	}
+}
+
+func (v *validator) validateParallel(path string, parallel *Parallel) {
+	if len(parallel.Steps) < 2 {
+		v.errorf(path, "parallel needs at least two steps")
+	}
+
+	for i, item := range parallel.Steps {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		if item.Step == nil {
+			v.errorf(itemPath, "parallel can only contain steps")
+			continue
+		}
+		v.validateStep(itemPath+".step", item.Step)
+	}
+}
+
+func (v *validator) validateStage(path string, stage *Stage) {
+	if len(stage.Steps) == 0 {
+		v.errorf(path, "stage has no steps")
+	}
+	if stage.Trigger != "" && stage.Trigger != "manual" && stage.Trigger != "automatic" {
+		v.errorf(path, "unknown trigger %q", stage.Trigger)
+	}
+	v.useDeployment(path, stage.Deployment)
+
+	for i, item := range stage.Steps {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		if item.Step == nil {
+			v.errorf(itemPath, "stage can only contain steps")
+			continue
+		}
+		if item.Step.Deployment != "" {
+			v.errorf(itemPath+".step", "steps of a stage take the deployment of the stage")
+		}
+		v.validateStep(itemPath+".step", item.Step)
+	}
+}
+
+func (v *validator) validateStep(path string, step *Step) {
+	if len(step.Script) == 0 {
+		v.errorf(path, "step has no script")
+	}
+	for i, script := range step.Script {
+		if script.Command == "" && script.Pipe == "" {
+			v.errorf(fmt.Sprintf("%s.script[%d]", path, i), "script line is empty")
+		}
+	}
+	if step.Trigger != "" && step.Trigger != "manual" && step.Trigger != "automatic" {
+		v.errorf(path, "unknown trigger %q", step.Trigger)
+	}
+	if step.Size != "" && !validSizes[step.Size] {
+		v.errorf(path, "unknown size %q", step.Size)
+	}
+	if step.MaxTime < 0 {
+		v.errorf(path, "max-time must be positive")
+	}
+	v.useDeployment(path, step.Deployment)
+
+	var definitions Definitions
+	if v.config.Definitions != nil {
+		definitions = *v.config.Definitions
+	}
+	for _, cache := range step.Caches {
+		if _, ok := definitions.Caches[cache]; !ok && !predefinedCaches[cache] {
+			v.errorf(path, "cache %q is not defined", cache)
+		}
+	}
+	for _, service := range step.Services {
+		if _, ok := definitions.Services[service]; !ok && service != "docker" {
+			v.errorf(path, "service %q is not defined", service)
+		}
+	}
+}
+
+// useDeployment records that the pipeline deploys to the environment, which Bitbucket only allows once.
+func (v *validator) useDeployment(path, deployment string) {
+	if deployment == "" {
+		return
+	}
+	if previous, ok := v.deployments[deployment]; ok {
+		v.errorf(path, "deployment %q is already used by %s", deployment, previous)
+		return
+	}
+	v.deployments[deployment] = path
+}
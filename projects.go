@@ -0,0 +1,314 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/k0kubun/pp"
+)
+
+const PermissionCreateRepo = "create-repo"
+
+type Projects struct {
+	c *Client
+}
+
+type ProjectLinks struct {
+	Self   Link
+	Html   Link
+	Avatar Link
+}
+
+// ProjectUserPermission is the permission explicitly granted to a user on a project.
+type ProjectUserPermission struct {
+	Type       string
+	Permission string
+	User       Account
+	Project    Project
+	Links      map[string]interface{}
+}
+
+// ProjectGroupPermission is the permission granted to a group on a project.
+type ProjectGroupPermission struct {
+	Type       string
+	Permission string
+	Group      Group
+	Project    Project
+	Links      map[string]interface{}
+}
+
+// DefaultReviewer is a default reviewer of a project. Reviewer_type tells whether the user was added on the
+// project or inherited from elsewhere.
+type DefaultReviewer struct {
+	Type          string
+	Reviewer_type string
+	User          Account
+}
+
+// List returns the projects of the workspace given as Owner.
+func (p *Projects) List(po *ProjectOptions) ([]*Project, error) {
+	urlStr := p.c.requestUrl("/workspaces/%s/projects", po.Owner)
+	response, err := p.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []*Project
+	if err := decodeValues(response, &projects); err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+// Get returns the project with the given Key.
+func (p *Projects) Get(po *ProjectOptions) (*Project, error) {
+	urlStr := p.c.requestUrl("/workspaces/%s/projects/%s", po.Owner, po.Key)
+	response, err := p.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeProject(response)
+}
+
+// Create creates a project. Key and Name are required.
+func (p *Projects) Create(po *ProjectOptions) (*Project, error) {
+	data := p.buildProjectBody(po)
+	urlStr := p.c.requestUrl("/workspaces/%s/projects", po.Owner)
+	response, err := p.c.execute(http.MethodPost, urlStr, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeProject(response)
+}
+
+// Update changes the project with the given Key. Bitbucket requires the Name to be sent on every update.
+func (p *Projects) Update(po *ProjectOptions) (*Project, error) {
+	data := p.buildProjectBody(po)
+	urlStr := p.c.requestUrl("/workspaces/%s/projects/%s", po.Owner, po.Key)
+	response, err := p.c.execute(http.MethodPut, urlStr, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeProject(response)
+}
+
+// Delete deletes the project with the given Key. It must not contain any repositories.
+func (p *Projects) Delete(po *ProjectOptions) error {
+	urlStr := p.c.requestUrl("/workspaces/%s/projects/%s", po.Owner, po.Key)
+	_, err := p.c.execute(http.MethodDelete, urlStr, "")
+	return err
+}
+
+// ListUserPermissions returns the users with an explicit permission on the project.
+func (p *Projects) ListUserPermissions(po *ProjectOptions) ([]*ProjectUserPermission, error) {
+	urlStr := p.c.requestUrl("/workspaces/%s/projects/%s/permissions-config/users", po.Owner, po.Key)
+	response, err := p.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var permissions []*ProjectUserPermission
+	if err := decodeValues(response, &permissions); err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+// GetUserPermission returns the explicit permission of the User, given by account id or UUID, on the project.
+func (p *Projects) GetUserPermission(po *ProjectOptions) (*ProjectUserPermission, error) {
+	urlStr := p.c.requestUrl("/workspaces/%s/projects/%s/permissions-config/users/%s", po.Owner, po.Key, po.User)
+	response, err := p.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var permission = new(ProjectUserPermission)
+	if err := decodeObject(response, permission); err != nil {
+		return nil, err
+	}
+
+	return permission, nil
+}
+
+// UpdateUserPermission grants the User the given Permission on the project, replacing any permission it had.
+func (p *Projects) UpdateUserPermission(po *ProjectOptions) (*ProjectUserPermission, error) {
+	data, err := p.buildPermissionBody(po)
+	if err != nil {
+		return nil, err
+	}
+	urlStr := p.c.requestUrl("/workspaces/%s/projects/%s/permissions-config/users/%s", po.Owner, po.Key, po.User)
+	response, err := p.c.execute(http.MethodPut, urlStr, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var permission = new(ProjectUserPermission)
+	if err := decodeObject(response, permission); err != nil {
+		return nil, err
+	}
+
+	return permission, nil
+}
+
+// DeleteUserPermission revokes the explicit permission of the User on the project.
+func (p *Projects) DeleteUserPermission(po *ProjectOptions) error {
+	urlStr := p.c.requestUrl("/workspaces/%s/projects/%s/permissions-config/users/%s", po.Owner, po.Key, po.User)
+	_, err := p.c.execute(http.MethodDelete, urlStr, "")
+	return err
+}
+
+// ListGroupPermissions returns the groups with a permission on the project.
+func (p *Projects) ListGroupPermissions(po *ProjectOptions) ([]*ProjectGroupPermission, error) {
+	urlStr := p.c.requestUrl("/workspaces/%s/projects/%s/permissions-config/groups", po.Owner, po.Key)
+	response, err := p.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var permissions []*ProjectGroupPermission
+	if err := decodeValues(response, &permissions); err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+// GetGroupPermission returns the permission of the Group, given by slug, on the project.
+func (p *Projects) GetGroupPermission(po *ProjectOptions) (*ProjectGroupPermission, error) {
+	urlStr := p.c.requestUrl("/workspaces/%s/projects/%s/permissions-config/groups/%s", po.Owner, po.Key, po.Group)
+	response, err := p.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var permission = new(ProjectGroupPermission)
+	if err := decodeObject(response, permission); err != nil {
+		return nil, err
+	}
+
+	return permission, nil
+}
+
+// UpdateGroupPermission grants the Group the given Permission on the project, replacing any permission it had.
+func (p *Projects) UpdateGroupPermission(po *ProjectOptions) (*ProjectGroupPermission, error) {
+	data, err := p.buildPermissionBody(po)
+	if err != nil {
+		return nil, err
+	}
+	urlStr := p.c.requestUrl("/workspaces/%s/projects/%s/permissions-config/groups/%s", po.Owner, po.Key, po.Group)
+	response, err := p.c.execute(http.MethodPut, urlStr, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var permission = new(ProjectGroupPermission)
+	if err := decodeObject(response, permission); err != nil {
+		return nil, err
+	}
+
+	return permission, nil
+}
+
+// DeleteGroupPermission revokes the permission of the Group on the project.
+func (p *Projects) DeleteGroupPermission(po *ProjectOptions) error {
+	urlStr := p.c.requestUrl("/workspaces/%s/projects/%s/permissions-config/groups/%s", po.Owner, po.Key, po.Group)
+	_, err := p.c.execute(http.MethodDelete, urlStr, "")
+	return err
+}
+
+// ListDefaultReviewers returns the default reviewers of the project.
+func (p *Projects) ListDefaultReviewers(po *ProjectOptions) ([]*DefaultReviewer, error) {
+	urlStr := p.c.requestUrl("/workspaces/%s/projects/%s/default-reviewers", po.Owner, po.Key)
+	response, err := p.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var reviewers []*DefaultReviewer
+	if err := decodeValues(response, &reviewers); err != nil {
+		return nil, err
+	}
+
+	return reviewers, nil
+}
+
+// AddDefaultReviewer adds the User, given by account id or UUID, to the default reviewers of the project.
+func (p *Projects) AddDefaultReviewer(po *ProjectOptions) error {
+	urlStr := p.c.requestUrl("/workspaces/%s/projects/%s/default-reviewers/%s", po.Owner, po.Key, po.User)
+	_, err := p.c.execute(http.MethodPut, urlStr, "")
+	return err
+}
+
+// RemoveDefaultReviewer takes the User out of the default reviewers of the project.
+func (p *Projects) RemoveDefaultReviewer(po *ProjectOptions) error {
+	urlStr := p.c.requestUrl("/workspaces/%s/projects/%s/default-reviewers/%s", po.Owner, po.Key, po.User)
+	_, err := p.c.execute(http.MethodDelete, urlStr, "")
+	return err
+}
+
+func (p *Projects) buildProjectBody(po *ProjectOptions) string {
+
+	body := map[string]interface{}{}
+
+	if po.Key != "" {
+		body["key"] = po.Key
+	}
+	if po.Name != "" {
+		body["name"] = po.Name
+	}
+	if po.Description != "" {
+		body["description"] = po.Description
+	}
+	if po.Is_private != nil {
+		body["is_private"] = *po.Is_private
+	}
+	if po.Avatar != "" {
+		body["links"] = map[string]interface{}{
+			"avatar": map[string]string{"href": po.Avatar},
+		}
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		pp.Println(err)
+		os.Exit(9)
+	}
+
+	return string(data)
+}
+
+func (p *Projects) buildPermissionBody(po *ProjectOptions) (string, error) {
+	switch po.Permission {
+	case PermissionRead, PermissionWrite, PermissionCreateRepo, PermissionAdmin:
+	default:
+		return "", fmt.Errorf("unknown permission %q", po.Permission)
+	}
+
+	body := map[string]interface{}{}
+
+	body["permission"] = po.Permission
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		pp.Println(err)
+		os.Exit(9)
+	}
+
+	return string(data), nil
+}
+
+func decodeProject(response interface{}) (*Project, error) {
+	var project = new(Project)
+	if err := decodeObject(response, project); err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
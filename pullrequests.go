@@ -48,14 +48,53 @@ func (p *PullRequests) Commits(po *PullRequestsOptions) (interface{}, error) {
 	return p.c.execute("GET", urlStr, "")
 }
 
-func (p *PullRequests) Patch(po *PullRequestsOptions) (interface{}, error) {
+// Patch returns the raw patch series of the pull request, narrowed down by the diff fields of the
+// PullRequestsOptions. Use ParsePatch or PatchCommits for a structured view.
+func (p *PullRequests) Patch(po *PullRequestsOptions) ([]byte, error) {
 	urlStr := GetApiBaseURL() + "/repositories/" + po.Owner + "/" + po.Repo_slug + "/pullrequests/" + po.Id + "/patch"
-	return p.c.execute("GET", urlStr, "")
+	urlStr += buildDiffQuery(pullRequestDiffOptions(po))
+	return p.c.executeRaw("GET", urlStr, "")
+}
+
+// PatchCommits fetches the patch series of the pull request and parses it into one PatchCommit per commit.
+func (p *PullRequests) PatchCommits(po *PullRequestsOptions) ([]*PatchCommit, error) {
+	raw, err := p.Patch(po)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParsePatch(raw)
 }
 
-func (p *PullRequests) Diff(po *PullRequestsOptions) (interface{}, error) {
+// Diff returns the raw unified diff of the pull request, shaped by the diff fields of the PullRequestsOptions
+// like Diff.GetDiff. Use ParseDiff or DiffFiles for a structured view.
+func (p *PullRequests) Diff(po *PullRequestsOptions) ([]byte, error) {
 	urlStr := GetApiBaseURL() + "/repositories/" + po.Owner + "/" + po.Repo_slug + "/pullrequests/" + po.Id + "/diff"
-	return p.c.execute("GET", urlStr, "")
+	urlStr += buildDiffQuery(pullRequestDiffOptions(po))
+	return p.c.executeRaw("GET", urlStr, "")
+}
+
+// DiffFiles fetches the diff of the pull request and parses it into files, hunks and lines.
+func (p *PullRequests) DiffFiles(po *PullRequestsOptions) ([]*DiffFile, error) {
+	raw, err := p.Diff(po)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseDiff(raw)
+}
+
+// Diffstat returns the per file change summary of the pull request, shaped by the diff fields of the
+// PullRequestsOptions like Diff.GetDiffstat.
+func (p *PullRequests) Diffstat(po *PullRequestsOptions) ([]*Diffstat, error) {
+	urlStr := GetApiBaseURL() + "/repositories/" + po.Owner + "/" + po.Repo_slug + "/pullrequests/" + po.Id + "/diffstat"
+	urlStr += buildDiffQuery(pullRequestDiffOptions(po))
+	response, err := p.c.execute("GET", urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeDiffstats(response)
 }
 
 func (p *PullRequests) Merge(po *PullRequestsOptions) (interface{}, error) {
@@ -80,6 +119,18 @@ func (p *PullRequests) GetComment(po *PullRequestsOptions) (interface{}, error)
 	return p.c.execute("GET", urlStr, "")
 }
 
+// pullRequestDiffOptions picks the fields shared with DiffOptions out of the PullRequestsOptions.
+func pullRequestDiffOptions(po *PullRequestsOptions) *DiffOptions {
+	return &DiffOptions{
+		Path:              po.Path,
+		Context:           po.Context,
+		Ignore_whitespace: po.Ignore_whitespace,
+		Binary:            po.Binary,
+		Renames:           po.Renames,
+		Topic:             po.Topic,
+	}
+}
+
 func (p *PullRequests) buildPullRequestBody(po *PullRequestsOptions) string {
 
 	body := map[string]interface{}{}
@@ -0,0 +1,208 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/k0kubun/pp"
+	"github.com/mitchellh/mapstructure"
+)
+
+type Refs struct {
+	c *Client
+}
+
+// Branch is a branch ref. Target is the commit at the head of the branch.
+type Branch struct {
+	Type                   string
+	Name                   string
+	Target                 Commit
+	Merge_strategies       []string
+	Default_merge_strategy string
+	Links                  map[string]interface{}
+}
+
+// Tag is a tag ref. Message, Date and Tagger are only set for annotated tags.
+type Tag struct {
+	Type    string
+	Name    string
+	Target  Commit
+	Message string
+	Date    string
+	Tagger  CommitAuthor
+	Links   map[string]interface{}
+}
+
+// ListBranches returns the branches of the repository. Query takes a BBQL filter, ie. name ~ "feature/",
+// and Sort a field to sort by, ie. -target.date.
+func (r *Refs) ListBranches(ro *RefsOptions) ([]*Branch, error) {
+	urlStr := r.c.requestUrl("/repositories/%s/%s/refs/branches", ro.Owner, ro.Repo_slug)
+	urlStr += r.buildRefsQuery(ro)
+	response, err := r.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var branches []*Branch
+	if err := decodeValues(response, &branches); err != nil {
+		return nil, err
+	}
+
+	return branches, nil
+}
+
+// GetBranch returns the branch with the given Name.
+func (r *Refs) GetBranch(ro *RefsOptions) (*Branch, error) {
+	urlStr := r.c.requestUrl("/repositories/%s/%s/refs/branches/%s", ro.Owner, ro.Repo_slug, ro.Name)
+	response, err := r.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeBranch(response)
+}
+
+// CreateBranch creates a branch called Name pointing at the Target commit hash.
+func (r *Refs) CreateBranch(ro *RefsOptions) (*Branch, error) {
+	data := r.buildRefBody(ro)
+	urlStr := r.c.requestUrl("/repositories/%s/%s/refs/branches", ro.Owner, ro.Repo_slug)
+	response, err := r.c.execute(http.MethodPost, urlStr, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeBranch(response)
+}
+
+// DeleteBranch deletes the branch with the given Name. The main branch cannot be deleted.
+func (r *Refs) DeleteBranch(ro *RefsOptions) error {
+	urlStr := r.c.requestUrl("/repositories/%s/%s/refs/branches/%s", ro.Owner, ro.Repo_slug, ro.Name)
+	_, err := r.c.execute(http.MethodDelete, urlStr, "")
+	return err
+}
+
+// ListTags returns the tags of the repository, filtered and sorted like ListBranches.
+func (r *Refs) ListTags(ro *RefsOptions) ([]*Tag, error) {
+	urlStr := r.c.requestUrl("/repositories/%s/%s/refs/tags", ro.Owner, ro.Repo_slug)
+	urlStr += r.buildRefsQuery(ro)
+	response, err := r.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []*Tag
+	if err := decodeValues(response, &tags); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// GetTag returns the tag with the given Name.
+func (r *Refs) GetTag(ro *RefsOptions) (*Tag, error) {
+	urlStr := r.c.requestUrl("/repositories/%s/%s/refs/tags/%s", ro.Owner, ro.Repo_slug, ro.Name)
+	response, err := r.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeTag(response)
+}
+
+// CreateTag creates a tag called Name pointing at the Target commit hash. A Message makes it an annotated tag.
+func (r *Refs) CreateTag(ro *RefsOptions) (*Tag, error) {
+	data := r.buildRefBody(ro)
+	urlStr := r.c.requestUrl("/repositories/%s/%s/refs/tags", ro.Owner, ro.Repo_slug)
+	response, err := r.c.execute(http.MethodPost, urlStr, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeTag(response)
+}
+
+// DeleteTag deletes the tag with the given Name.
+func (r *Refs) DeleteTag(ro *RefsOptions) error {
+	urlStr := r.c.requestUrl("/repositories/%s/%s/refs/tags/%s", ro.Owner, ro.Repo_slug, ro.Name)
+	_, err := r.c.execute(http.MethodDelete, urlStr, "")
+	return err
+}
+
+func (r *Refs) buildRefsQuery(ro *RefsOptions) string {
+
+	p := url.Values{}
+
+	if ro.Query != "" {
+		p.Add("q", ro.Query)
+	}
+	if ro.Sort != "" {
+		p.Add("sort", ro.Sort)
+	}
+
+	if len(p) == 0 {
+		return ""
+	}
+	return "?" + p.Encode()
+}
+
+func (r *Refs) buildRefBody(ro *RefsOptions) string {
+
+	body := map[string]interface{}{}
+
+	body["name"] = ro.Name
+	body["target"] = map[string]string{
+		"hash": ro.Target,
+	}
+	if ro.Message != "" {
+		body["message"] = ro.Message
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		pp.Println(err)
+		os.Exit(9)
+	}
+
+	return string(data)
+}
+
+func decodeBranch(response interface{}) (*Branch, error) {
+	branchMap, ok := response.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected response of type %T", response)
+	}
+
+	if branchMap["type"] == "error" {
+		return nil, DecodeError(branchMap)
+	}
+
+	var branch = new(Branch)
+	err := mapstructure.Decode(branchMap, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	return branch, nil
+}
+
+func decodeTag(response interface{}) (*Tag, error) {
+	tagMap, ok := response.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected response of type %T", response)
+	}
+
+	if tagMap["type"] == "error" {
+		return nil, DecodeError(tagMap)
+	}
+
+	var tag = new(Tag)
+	err := mapstructure.Decode(tagMap, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
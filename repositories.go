@@ -15,33 +15,58 @@ type Repositories struct {
 	Diff               *Diff
 	BranchRestrictions *BranchRestrictions
 	Webhooks           *Webhooks
+	Refs               *Refs
+	Permissions        *Permissions
+	DeployKeys         *DeployKeys
+	Pipelines          *Pipelines
+	Deployments        *Deployments
 	repositories
 }
 
-func (r *Repositories) ListForAccount(ro *RepositoriesOptions) (interface{}, error) {
+func (r *Repositories) ListForAccount(ro *RepositoriesOptions) (*RepositoriesPage, error) {
 	urlStr := r.c.requestUrl("/repositories/%s", ro.Owner)
 	if ro.Role != "" {
 		urlStr += "?role=" + ro.Role
 	}
-	return r.c.execute("GET", urlStr, "")
+	response, err := r.c.execute("GET", urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeRepositoriesPage(response)
 }
 
-func (r *Repositories) ListForTeam(ro *RepositoriesOptions) (interface{}, error) {
+func (r *Repositories) ListForTeam(ro *RepositoriesOptions) (*RepositoriesPage, error) {
 	urlStr := r.c.requestUrl("/repositories/%s", ro.Owner)
 	if ro.Role != "" {
 		urlStr += "?role=" + ro.Role
 	}
-	return r.c.execute("GET", urlStr, "")
+	response, err := r.c.execute("GET", urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeRepositoriesPage(response)
 }
 
-func (r *Repositories) ListPublic() (interface{}, error) {
-	urlStr := r.c.requestUrl("/repositories/", "")
-	return r.c.execute("GET", urlStr, "")
+func (r *Repositories) ListPublic() (*RepositoriesPage, error) {
+	urlStr := r.c.requestUrl("/repositories/")
+	response, err := r.c.execute("GET", urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeRepositoriesPage(response)
 }
 
 // ListForProject returns a pagenated list of repositories for the given project
-func (r *Repositories) ListForProject(ro *ProjectRepositoryOptions) (interface{}, error) {
+func (r *Repositories) ListForProject(ro *ProjectRepositoryOptions) (*RepositoriesPage, error) {
 	values, _ := url.ParseQuery(fmt.Sprintf("q=project.key=\"%s\"&pagelen=%d&page=%d", ro.Project, ro.PageLength, ro.Page))
 	urlStr := r.c.requestUrl("/repositories/%s?%s", ro.Owner, values.Encode())
-	return r.c.execute("GET", urlStr, "")
+	response, err := r.c.execute("GET", urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeRepositoriesPage(response)
 }
@@ -2,6 +2,7 @@ package bitbucket
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/url"
 	"os"
@@ -13,21 +14,102 @@ import (
 )
 
 type Project struct {
-	Key  string
+	Key                        string
+	Name                       string
+	Type                       string
+	Uuid                       string
+	Description                string
+	Is_private                 bool
+	Has_publicly_visible_repos bool
+	Created_on                 string
+	Updated_on                 string
+	Owner                      Account
+	Workspace                  Workspace
+	Links                      ProjectLinks
+}
+
+// Avatar returns the URL of the project's avatar.
+func (p *Project) Avatar() string {
+	return p.Links.Avatar.Href
+}
+
+// Link is a single hyperlink of a resource. Name is only set for clone links, where it is https or ssh.
+type Link struct {
+	Href string
 	Name string
 }
 
+type RepositoryLinks struct {
+	Self         Link
+	Html         Link
+	Avatar       Link
+	Pullrequests Link
+	Commits      Link
+	Forks        Link
+	Watchers     Link
+	Branches     Link
+	Tags         Link
+	Downloads    Link
+	Source       Link
+	Hooks        Link
+	Clone        []Link
+}
+
 type Repository struct {
 	c *Client
 
-	Project     Project
+	Uuid        string
+	Name        string
 	Slug        string
 	Full_name   string
 	Description string
+	Scm         string
+	Website     string
+	Language    string
+	Size        int
+	Is_private  bool
+	Has_issues  bool
+	Has_wiki    bool
 	Fork_policy string
+	Created_on  string
+	Updated_on  string
 	Type        string
-	Owner       map[string]interface{}
-	Links       map[string]interface{}
+	Mainbranch  *Branch
+	Owner       Account
+	Workspace   Workspace
+	Project     Project
+	Links       RepositoryLinks
+	Parent      *Repository
+}
+
+// RepositoriesPage is a page of repositories. The list calls follow the next links themselves, so unless a
+// page was asked for explicitly it holds all matching repositories.
+type RepositoriesPage struct {
+	Page    int
+	Pagelen int
+	Size    int
+	Next    string
+	Values  []*Repository
+}
+
+// CloneURL returns the clone URL for the given protocol, https or ssh, or "" if there is none.
+func (r *Repository) CloneURL(protocol string) string {
+	for _, l := range r.Links.Clone {
+		if l.Name == protocol {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+// HTTPSCloneURL returns the https clone URL of the repository.
+func (r *Repository) HTTPSCloneURL() string {
+	return r.CloneURL("https")
+}
+
+// SSHCloneURL returns the ssh clone URL of the repository.
+func (r *Repository) SSHCloneURL() string {
+	return r.CloneURL("ssh")
 }
 
 type Pipeline struct {
@@ -47,8 +129,8 @@ type PipelineVariable struct {
 type PipelineKeyPair struct {
 	Type       string
 	Uuid       string
-	PublicKey  string
-	PrivateKey string
+	PublicKey  string `mapstructure:"public_key"`
+	PrivateKey string `mapstructure:"private_key"`
 }
 
 func (r *Repository) Create(ro *RepositoryOptions) (*Repository, error) {
@@ -72,6 +154,30 @@ func (r *Repository) Get(ro *RepositoryOptions) (*Repository, error) {
 	return decodeRepository(response)
 }
 
+// Update changes the settings of an existing repository. Only the fields set in the RepositoryOptions are sent,
+// everything else is left as it is.
+func (r *Repository) Update(ro *RepositoryOptions) (*Repository, error) {
+	data := r.buildRepositoryBody(ro)
+	urlStr := r.c.requestUrl("/repositories/%s/%s", ro.Owner, ro.Repo_slug)
+	response, err := r.c.execute(http.MethodPut, urlStr, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeRepository(response)
+}
+
+// Rename gives the repository a new name. Bitbucket derives a new slug from it, which is set on the returned
+// Repository; the old slug keeps redirecting for a while.
+func (r *Repository) Rename(ro *RepositoryOptions, name string) (*Repository, error) {
+	return r.Update(&RepositoryOptions{Owner: ro.Owner, Repo_slug: ro.Repo_slug, Name: name})
+}
+
+// TransferToProject moves the repository into the project with the given key, within the same workspace.
+func (r *Repository) TransferToProject(ro *RepositoryOptions, projectKey string) (*Repository, error) {
+	return r.Update(&RepositoryOptions{Owner: ro.Owner, Repo_slug: ro.Repo_slug, Project: projectKey})
+}
+
 func (r *Repository) Delete(ro *RepositoryOptions) (interface{}, error) {
 	urlStr := r.c.requestUrl("/repositories/%s/%s", ro.Owner, ro.Repo_slug)
 	return r.c.execute("DELETE", urlStr, "")
@@ -82,9 +188,93 @@ func (r *Repository) ListWatchers(ro *RepositoryOptions) (interface{}, error) {
 	return r.c.execute("GET", urlStr, "")
 }
 
-func (r *Repository) ListForks(ro *RepositoryOptions) (interface{}, error) {
+// ListForks returns the direct forks of the repository.
+func (r *Repository) ListForks(ro *RepositoryOptions) (*RepositoriesPage, error) {
 	urlStr := r.c.requestUrl("/repositories/%s/%s/forks", ro.Owner, ro.Repo_slug)
-	return r.c.execute("GET", urlStr, "")
+	response, err := r.c.execute("GET", urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeRepositoriesPage(response)
+}
+
+// CreateFork forks the repository into the given Workspace, or the authenticated user's own workspace when it
+// is empty. The returned Repository has its Parent set to the forked repository.
+func (r *Repository) CreateFork(rfo *RepositoryForkOptions) (*Repository, error) {
+	data := r.buildForkBody(rfo)
+	urlStr := r.c.requestUrl("/repositories/%s/%s/forks", rfo.Owner, rfo.Repo_slug)
+	response, err := r.c.execute(http.MethodPost, urlStr, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeRepository(response)
+}
+
+// ForkNode is a repository in a fork tree. Parent is nil for the root of the fork network.
+type ForkNode struct {
+	Repository *Repository
+	Parent     *ForkNode
+	Forks      []*ForkNode
+}
+
+// GetForkTree returns the whole fork network the repository belongs to. It first follows the parents up to
+// the repository that is not a fork, then walks down through the forks, the forks of those and so on. A parent
+// that cannot be read, ie. because it is private or deleted, ends the climb and the highest readable ancestor
+// becomes the root. Repositories that were already visited are skipped, so the walk always terminates.
+func (r *Repository) GetForkTree(ro *RepositoryOptions) (*ForkNode, error) {
+	repo, err := r.Get(ro)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{repo.Full_name: true}
+	for repo.Parent != nil && repo.Parent.Full_name != "" && !seen[repo.Parent.Full_name] {
+		owner, slug := splitFullName(repo.Parent.Full_name)
+		parent, err := r.Get(&RepositoryOptions{Owner: owner, Repo_slug: slug})
+		if err != nil {
+			break
+		}
+		repo = parent
+		seen[repo.Full_name] = true
+	}
+
+	root := &ForkNode{Repository: repo}
+	visited := map[string]bool{repo.Full_name: true}
+	queue := []*ForkNode{root}
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		owner, slug := splitFullName(node.Repository.Full_name)
+		forks, err := r.ListForks(&RepositoryOptions{Owner: owner, Repo_slug: slug})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, fork := range forks.Values {
+			if visited[fork.Full_name] {
+				continue
+			}
+			visited[fork.Full_name] = true
+
+			child := &ForkNode{Repository: fork, Parent: node}
+			node.Forks = append(node.Forks, child)
+			queue = append(queue, child)
+		}
+	}
+
+	return root, nil
+}
+
+func splitFullName(fullName string) (string, string) {
+	parts := strings.SplitN(fullName, "/", 2)
+	if len(parts) != 2 {
+		return fullName, ""
+	}
+	return parts[0], parts[1]
 }
 
 // ListDefaultReviewers returns the list of default reviewers for the given repo
@@ -119,6 +309,7 @@ func (r *Repository) RemoveDefaultReviewer(ro *RepositoryOptions, username strin
 
 // UploadFile takes in the full path of the desired file, ie /src/main/test.txt, and uses the content string to
 // create the file in the specified repo. The Owner and Repo_slug fields are needed from the RepositoryOptions.
+// CommitFiles covers several files, deletions, binary content and commit metadata in a single commit.
 func (r *Repository) UploadFile(ro *RepositoryOptions, branch, filePath, content string) (*http.Response, error) {
 	urlStr := r.c.requestUrl("/repositories/%s/%s/src", ro.Owner, ro.Repo_slug)
 	client := http.DefaultClient
@@ -131,11 +322,7 @@ func (r *Repository) UploadFile(ro *RepositoryOptions, branch, filePath, content
 	if err != nil {
 		return nil, err
 	}
-	if r.c.Auth.user != "" && r.c.Auth.password != "" {
-		req.SetBasicAuth(r.c.Auth.user, r.c.Auth.password)
-	} else if r.c.Auth.token.Valid() {
-		r.c.Auth.token.SetAuthHeader(req)
-	}
+	r.c.authenticate(req)
 
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 	req.Header.Add("Content-Length", strconv.Itoa(len(data.Encode())))
@@ -149,9 +336,15 @@ func (r *Repository) UploadFile(ro *RepositoryOptions, branch, filePath, content
 	return resp, nil
 }
 
+// GetFile returns the raw content of filePath at the given commit hash or branch. An empty hash means the
+// head of the repository's main branch.
 func (r *Repository) GetFile(ro *RepositoryOptions, filePath, hash string) ([]byte, error) {
 	if hash == "" {
-		hash = "master"
+		mainBranch, err := r.GetMainBranch(ro)
+		if err != nil {
+			return nil, err
+		}
+		hash = mainBranch
 	}
 
 	urlStr := r.c.requestUrl("/repositories/%s/%s/src/%s/%s", ro.Owner, ro.Repo_slug, hash, filePath)
@@ -170,16 +363,49 @@ func (r *Repository) UpdatePipelineConfig(rpo *RepositoryPipelineOptions) (*Pipe
 	return decodePipelineRepository(response)
 }
 
+// AddPipelineVariable adds a pipeline variable to the repository. The pipeline variable methods of Repository
+// are shorthands for the variable methods of Pipelines on the repository scope.
 func (r *Repository) AddPipelineVariable(rpvo *RepositoryPipelineVariableOptions) (*PipelineVariable, error) {
-	data := r.buildPipelineVariableBody(rpvo)
-	urlStr := r.c.requestUrl("/repositories/%s/%s/pipelines_config/variables/", rpvo.Owner, rpvo.Repo_slug)
+	return r.c.Repositories.Pipelines.CreateVariable(pipelineVariableOptions(rpvo))
+}
 
-	response, err := r.c.execute("POST", urlStr, data)
-	if err != nil {
-		return nil, err
-	}
+// ListPipelineVariables returns the pipeline variables of the repository. Secured variables come without a Value.
+func (r *Repository) ListPipelineVariables(rpvo *RepositoryPipelineVariableOptions) ([]*PipelineVariable, error) {
+	return r.c.Repositories.Pipelines.ListVariables(pipelineVariableOptions(rpvo))
+}
+
+// GetPipelineVariable returns the pipeline variable with the given Uuid.
+func (r *Repository) GetPipelineVariable(rpvo *RepositoryPipelineVariableOptions) (*PipelineVariable, error) {
+	return r.c.Repositories.Pipelines.GetVariable(pipelineVariableOptions(rpvo))
+}
+
+// UpdatePipelineVariable replaces the key, value and secured flag of the pipeline variable with the given Uuid.
+func (r *Repository) UpdatePipelineVariable(rpvo *RepositoryPipelineVariableOptions) (*PipelineVariable, error) {
+	return r.c.Repositories.Pipelines.UpdateVariable(pipelineVariableOptions(rpvo))
+}
+
+// DeletePipelineVariable deletes the pipeline variable with the given Uuid.
+func (r *Repository) DeletePipelineVariable(rpvo *RepositoryPipelineVariableOptions) error {
+	return r.c.Repositories.Pipelines.DeleteVariable(pipelineVariableOptions(rpvo))
+}
+
+// UpsertPipelineVariable adds the pipeline variable, or updates the existing variable with the same Key, as
+// Pipelines.UpsertVariable does.
+func (r *Repository) UpsertPipelineVariable(rpvo *RepositoryPipelineVariableOptions) (*PipelineVariable, error) {
+	return r.c.Repositories.Pipelines.UpsertVariable(pipelineVariableOptions(rpvo))
+}
 
-	return decodePipelineVariableRepository(response)
+// pipelineVariableOptions scopes the RepositoryPipelineVariableOptions to the repository.
+func pipelineVariableOptions(rpvo *RepositoryPipelineVariableOptions) *PipelineVariableOptions {
+	return &PipelineVariableOptions{
+		Scope:     PipelineVariableScopeRepository,
+		Owner:     rpvo.Owner,
+		Repo_slug: rpvo.Repo_slug,
+		Uuid:      rpvo.Uuid,
+		Key:       rpvo.Key,
+		Value:     rpvo.Value,
+		Secured:   rpvo.Secured,
+	}
 }
 
 func (r *Repository) AddPipelineKeyPair(rpkpo *RepositoryPipelineKeyPairOptions) (*PipelineKeyPair, error) {
@@ -212,11 +438,11 @@ func (r *Repository) buildRepositoryBody(ro *RepositoryOptions) string {
 	if ro.Scm != "" {
 		body["scm"] = ro.Scm
 	}
-	//if ro.Scm != "" {
-	//		body["name"] = ro.Name
-	//}
-	if ro.Is_private != "" {
-		body["is_private"] = ro.Is_private
+	if ro.Name != "" {
+		body["name"] = ro.Name
+	}
+	if ro.Is_private != nil {
+		body["is_private"] = *ro.Is_private
 	}
 	if ro.Description != "" {
 		body["description"] = ro.Description
@@ -227,40 +453,61 @@ func (r *Repository) buildRepositoryBody(ro *RepositoryOptions) string {
 	if ro.Language != "" {
 		body["language"] = ro.Language
 	}
-	if ro.Has_issues != "" {
-		body["has_issues"] = ro.Has_issues
+	if ro.Has_issues != nil {
+		body["has_issues"] = *ro.Has_issues
 	}
-	if ro.Has_wiki != "" {
-		body["has_wiki"] = ro.Has_wiki
+	if ro.Has_wiki != nil {
+		body["has_wiki"] = *ro.Has_wiki
 	}
 	if ro.Project != "" {
 		body["project"] = map[string]string{
 			"key": ro.Project,
 		}
 	}
+	if ro.Mainbranch != "" {
+		body["mainbranch"] = map[string]string{
+			"name": ro.Mainbranch,
+		}
+	}
 
 	return r.buildJsonBody(body)
 }
 
-func (r *Repository) buildPipelineBody(rpo *RepositoryPipelineOptions) string {
+func (r *Repository) buildForkBody(rfo *RepositoryForkOptions) string {
 
 	body := map[string]interface{}{}
 
-	body["enabled"] = rpo.Enabled
+	if rfo.Workspace != "" {
+		body["workspace"] = map[string]string{
+			"slug": rfo.Workspace,
+		}
+	}
+	if rfo.Name != "" {
+		body["name"] = rfo.Name
+	}
+	if rfo.Description != "" {
+		body["description"] = rfo.Description
+	}
+	if rfo.Is_private != nil {
+		body["is_private"] = *rfo.Is_private
+	}
+	if rfo.Fork_policy != "" {
+		body["fork_policy"] = rfo.Fork_policy
+	}
+	if rfo.Project != "" {
+		body["project"] = map[string]string{
+			"key": rfo.Project,
+		}
+	}
 
 	return r.buildJsonBody(body)
 }
 
-func (r *Repository) buildPipelineVariableBody(rpvo *RepositoryPipelineVariableOptions) string {
+func (r *Repository) buildPipelineBody(rpo *RepositoryPipelineOptions) string {
 
 	body := map[string]interface{}{}
 
-	if rpvo.Uuid != "" {
-		body["uuid"] = rpvo.Uuid
-	}
-	body["key"] = rpvo.Key
-	body["value"] = rpvo.Value
-	body["secured"] = rpvo.Secured
+	body["enabled"] = rpo.Enabled
 
 	return r.buildJsonBody(body)
 }
@@ -280,7 +527,10 @@ func (r *Repository) buildPipelineKeyPairBody(rpkpo *RepositoryPipelineKeyPairOp
 }
 
 func decodeRepository(repoResponse interface{}) (*Repository, error) {
-	repoMap := repoResponse.(map[string]interface{})
+	repoMap, ok := repoResponse.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected response of type %T", repoResponse)
+	}
 
 	if repoMap["type"] == "error" {
 		return nil, DecodeError(repoMap)
@@ -295,8 +545,30 @@ func decodeRepository(repoResponse interface{}) (*Repository, error) {
 	return repository, nil
 }
 
+func decodeRepositoriesPage(response interface{}) (*RepositoriesPage, error) {
+	pageMap, ok := response.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected response of type %T", response)
+	}
+
+	if pageMap["type"] == "error" {
+		return nil, DecodeError(pageMap)
+	}
+
+	var page = new(RepositoriesPage)
+	err := mapstructure.Decode(pageMap, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
 func decodePipelineRepository(repoResponse interface{}) (*Pipeline, error) {
-	repoMap := repoResponse.(map[string]interface{})
+	repoMap, ok := repoResponse.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected response of type %T", repoResponse)
+	}
 
 	if repoMap["type"] == "error" {
 		return nil, DecodeError(repoMap)
@@ -312,7 +584,10 @@ func decodePipelineRepository(repoResponse interface{}) (*Pipeline, error) {
 }
 
 func decodePipelineVariableRepository(repoResponse interface{}) (*PipelineVariable, error) {
-	repoMap := repoResponse.(map[string]interface{})
+	repoMap, ok := repoResponse.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected response of type %T", repoResponse)
+	}
 
 	if repoMap["type"] == "error" {
 		return nil, DecodeError(repoMap)
@@ -328,7 +603,10 @@ func decodePipelineVariableRepository(repoResponse interface{}) (*PipelineVariab
 }
 
 func decodePipelineKeyPairRepository(repoResponse interface{}) (*PipelineKeyPair, error) {
-	repoMap := repoResponse.(map[string]interface{})
+	repoMap, ok := repoResponse.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected response of type %T", repoResponse)
+	}
 
 	if repoMap["type"] == "error" {
 		return nil, DecodeError(repoMap)
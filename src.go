@@ -0,0 +1,360 @@
+package bitbucket
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	CommitFileTypeFile      = "commit_file"
+	CommitFileTypeDirectory = "commit_directory"
+)
+
+// CommitFile is a file or directory at a given commit, as returned by the src and diffstat endpoints.
+// Attributes may contain "executable", "link", "binary", "lfs" and "subrepository".
+type CommitFile struct {
+	Type         string
+	Path         string
+	Escaped_path string
+	Size         int
+	Mimetype     string
+	Attributes   []string
+	Commit       *Commit
+	Links        map[string]interface{}
+}
+
+// IsDir reports whether the entry is a directory.
+func (cf *CommitFile) IsDir() bool {
+	return cf.Type == CommitFileTypeDirectory
+}
+
+// IsLink reports whether the entry is a symbolic link.
+func (cf *CommitFile) IsLink() bool {
+	return cf.hasAttribute("link")
+}
+
+// IsExecutable reports whether the entry has the executable bit set.
+func (cf *CommitFile) IsExecutable() bool {
+	return cf.hasAttribute("executable")
+}
+
+// IsLFS reports whether the entry is stored in Git LFS.
+func (cf *CommitFile) IsLFS() bool {
+	return cf.hasAttribute("lfs")
+}
+
+func (cf *CommitFile) hasAttribute(name string) bool {
+	for _, a := range cf.Attributes {
+		if a == name {
+			return true
+		}
+	}
+	return false
+}
+
+// GetMainBranch returns the name of the repository's main branch, as configured in Bitbucket.
+func (r *Repository) GetMainBranch(ro *RepositoryOptions) (string, error) {
+	repo, err := r.Get(ro)
+	if err != nil {
+		return "", err
+	}
+
+	if repo.Mainbranch == nil || repo.Mainbranch.Name == "" {
+		return "", errors.New("repository has no main branch")
+	}
+
+	return repo.Mainbranch.Name, nil
+}
+
+// ListFiles lists the directory at Path, or the repository root when Path is empty, at the given Revision.
+// A Max_depth above 1 also returns the contents of subdirectories down to that depth.
+func (r *Repository) ListFiles(rfo *RepositoryFilesOptions) ([]*CommitFile, error) {
+	urlStr, err := r.buildSrcUrl(rfo)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(urlStr, "/") {
+		urlStr += "/"
+	}
+
+	p := url.Values{}
+	if rfo.Max_depth > 0 {
+		p.Add("max_depth", strconv.Itoa(rfo.Max_depth))
+	}
+	if rfo.Query != "" {
+		p.Add("q", rfo.Query)
+	}
+	if rfo.Sort != "" {
+		p.Add("sort", rfo.Sort)
+	}
+	if len(p) > 0 {
+		urlStr += "?" + p.Encode()
+	}
+
+	response, err := r.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeCommitFiles(response)
+}
+
+// GetFileMeta returns the metadata of the file or directory at Path and Revision instead of its contents.
+func (r *Repository) GetFileMeta(rfo *RepositoryFilesOptions) (*CommitFile, error) {
+	urlStr, err := r.buildSrcUrl(rfo)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := r.c.execute(http.MethodGet, urlStr+"?format=meta", "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeCommitFile(response)
+}
+
+// CommitFiles creates a single commit that writes the given Files and removes the Delete_files. The content
+// is sent as multipart form data, so binary files are supported. It returns the hash of the new commit; a
+// rejected commit is reported as a *BitbucketError carrying the message and fields Bitbucket returned.
+func (r *Repository) CommitFiles(rco *RepositoryCommitOptions) (string, error) {
+	if len(rco.Files) == 0 && len(rco.Delete_files) == 0 {
+		return "", errors.New("nothing to commit")
+	}
+
+	body, contentType, err := r.buildCommitBody(rco)
+	if err != nil {
+		return "", err
+	}
+
+	urlStr := r.c.requestUrl("/repositories/%s/%s/src", rco.Owner, rco.Repo_slug)
+	req, err := http.NewRequest(http.MethodPost, urlStr, body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", contentType)
+	r.c.authenticate(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return "", decodeErrorResponse(resp)
+	}
+
+	// The new commit is only reported through the Location header, ie. .../commit/<hash>.
+	location, err := resp.Location()
+	if err != nil {
+		return "", &BitbucketError{Message: "commit created without a Location header", StatusCode: resp.StatusCode}
+	}
+
+	return path.Base(location.Path), nil
+}
+
+func (r *Repository) buildCommitBody(rco *RepositoryCommitOptions) (*bytes.Buffer, string, error) {
+	body := new(bytes.Buffer)
+	w := multipart.NewWriter(body)
+
+	fields := [][2]string{
+		{"message", rco.Message},
+		{"author", rco.Author},
+		{"branch", rco.Branch},
+		{"parents", strings.Join(rco.Parents, ",")},
+	}
+	for _, field := range fields {
+		if field[1] == "" {
+			continue
+		}
+		if err := w.WriteField(field[0], field[1]); err != nil {
+			return nil, "", err
+		}
+	}
+	for _, filePath := range rco.Delete_files {
+		if err := w.WriteField("files", "/"+strings.TrimPrefix(filePath, "/")); err != nil {
+			return nil, "", err
+		}
+	}
+
+	// File fields are named after their path. The leading slash keeps them apart from the fields above.
+	for filePath, content := range rco.Files {
+		name := "/" + strings.TrimPrefix(filePath, "/")
+		part, err := w.CreateFormFile(name, path.Base(name))
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := part.Write(content); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return body, w.FormDataContentType(), nil
+}
+
+// FileHistory iterates over the versions of a file, newest first, fetching one page at a time. Renames are
+// followed, so the Path of older entries may differ from the one the iteration started with. The content of
+// a version can be fetched with GetFile and the entry's Commit.Hash. Call Next until it returns false, then
+// check Err.
+type FileHistory struct {
+	r       *Repository
+	rfo     *RepositoryFilesOptions
+	next    string
+	started bool
+	page    []*CommitFile
+	current *CommitFile
+	err     error
+}
+
+// FileHistory returns an iterator over the commits that touched Path, starting at Revision. Query and Sort
+// are passed on to the filehistory endpoint.
+func (r *Repository) FileHistory(rfo *RepositoryFilesOptions) *FileHistory {
+	return &FileHistory{r: r, rfo: rfo}
+}
+
+// Next advances to the next entry. It returns false when the history is exhausted or a request failed.
+func (fh *FileHistory) Next() bool {
+	if fh.err != nil {
+		return false
+	}
+
+	for len(fh.page) == 0 {
+		if fh.started && fh.next == "" {
+			return false
+		}
+		if err := fh.fetch(); err != nil {
+			fh.err = err
+			return false
+		}
+	}
+
+	fh.current, fh.page = fh.page[0], fh.page[1:]
+	return true
+}
+
+// File returns the entry Next advanced to.
+func (fh *FileHistory) File() *CommitFile {
+	return fh.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (fh *FileHistory) Err() error {
+	return fh.err
+}
+
+func (fh *FileHistory) fetch() error {
+	urlStr := fh.next
+	if !fh.started {
+		revision := fh.rfo.Revision
+		if revision == "" {
+			mainBranch, err := fh.r.GetMainBranch(&RepositoryOptions{Owner: fh.rfo.Owner, Repo_slug: fh.rfo.Repo_slug})
+			if err != nil {
+				return err
+			}
+			revision = mainBranch
+		}
+		urlStr = fh.r.c.requestUrl("/repositories/%s/%s/filehistory/%s/%s", fh.rfo.Owner, fh.rfo.Repo_slug, revision, strings.TrimPrefix(fh.rfo.Path, "/"))
+
+		p := url.Values{}
+		if fh.rfo.Query != "" {
+			p.Add("q", fh.rfo.Query)
+		}
+		if fh.rfo.Sort != "" {
+			p.Add("sort", fh.rfo.Sort)
+		}
+		if len(p) > 0 {
+			urlStr += "?" + p.Encode()
+		}
+
+		var err error
+		urlStr, err = fh.r.c.withPagelen(urlStr)
+		if err != nil {
+			return err
+		}
+		fh.started = true
+	}
+
+	b, err := fh.r.c.executeRaw(http.MethodGet, urlStr, "")
+	if err != nil {
+		return err
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(b, &response); err != nil {
+		return err
+	}
+
+	fh.page, err = decodeCommitFiles(response)
+	if err != nil {
+		return err
+	}
+	fh.next, _ = response["next"].(string)
+
+	return nil
+}
+
+func (r *Repository) buildSrcUrl(rfo *RepositoryFilesOptions) (string, error) {
+	revision := rfo.Revision
+	if revision == "" {
+		mainBranch, err := r.GetMainBranch(&RepositoryOptions{Owner: rfo.Owner, Repo_slug: rfo.Repo_slug})
+		if err != nil {
+			return "", err
+		}
+		revision = mainBranch
+	}
+
+	return r.c.requestUrl("/repositories/%s/%s/src/%s/%s", rfo.Owner, rfo.Repo_slug, revision, strings.TrimPrefix(rfo.Path, "/")), nil
+}
+
+func decodeCommitFiles(response interface{}) ([]*CommitFile, error) {
+	responseMap, ok := response.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected response of type %T", response)
+	}
+
+	if responseMap["type"] == "error" {
+		return nil, DecodeError(responseMap)
+	}
+
+	var files []*CommitFile
+	err := mapstructure.Decode(responseMap["values"], &files)
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+func decodeCommitFile(response interface{}) (*CommitFile, error) {
+	responseMap, ok := response.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected response of type %T", response)
+	}
+
+	if responseMap["type"] == "error" {
+		return nil, DecodeError(responseMap)
+	}
+
+	var file = new(CommitFile)
+	err := mapstructure.Decode(responseMap, file)
+	if err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
@@ -1,58 +1,125 @@
 package bitbucket
 
+import "fmt"
+
+// Teams wraps the deprecated /teams endpoints. Where Bitbucket offers a replacement the calls are served by
+// the Workspaces service; the untyped calls reshape the workspace responses into the team and user accounts
+// the /teams endpoints returned, so existing callers keep working.
 type Teams struct {
 	c *Client
 }
 
+// teamRoles maps the team roles to the workspace roles that replaced them.
+var teamRoles = map[string]string{
+	"admin":       "owner",
+	"contributor": "collaborator",
+	"member":      "member",
+}
+
 func (t *Teams) List(role string) (interface{}, error) {
-	urlStr := t.c.requestUrl("/teams/?role=%s", role)
-	return t.c.execute("GET", urlStr, "")
+	if workspaceRole, ok := teamRoles[role]; ok {
+		role = workspaceRole
+	}
+	response, err := t.c.Workspaces.list(&WorkspaceOptions{Role: role})
+	if err != nil {
+		return nil, err
+	}
+
+	return teamPage(response, teamAccount)
 }
 
 func (t *Teams) Profile(teamname string) (interface{}, error) {
-	urlStr := t.c.requestUrl("/teams/%s/", teamname)
-	return t.c.execute("GET", urlStr, "")
+	response, err := t.c.Workspaces.get(teamname)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace, ok := response.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected response of type %T", response)
+	}
+
+	return teamAccount(workspace), nil
+}
+
+// GetProfile returns the team as a typed account, built from the workspace that replaced it.
+func (t *Teams) GetProfile(teamname string) (*Team, error) {
+	workspace, err := t.c.Workspaces.Get(&WorkspaceOptions{Owner: teamname})
+	if err != nil {
+		return nil, err
+	}
+
+	team := &Team{Account: Account{
+		Type:         AccountTypeTeam,
+		Uuid:         workspace.Uuid,
+		Username:     workspace.Slug,
+		Display_name: workspace.Name,
+		Created_on:   workspace.Created_on,
+	}}
+	if err := decodeObject(workspace.Links, &team.Links); err != nil {
+		return nil, err
+	}
+
+	return team, nil
 }
 
 func (t *Teams) Members(teamname string) (interface{}, error) {
-	urlStr := t.c.requestUrl("/teams/%s/members", teamname)
-	return t.c.execute("GET", urlStr, "")
+	response, err := t.c.Workspaces.members(teamname)
+	if err != nil {
+		return nil, err
+	}
+
+	return teamPage(response, func(membership map[string]interface{}) interface{} {
+		return membership["user"]
+	})
 }
 
+// GetMembers returns the members of the team as typed users.
+func (t *Teams) GetMembers(teamname string) ([]*User, error) {
+	memberships, err := t.c.Workspaces.ListMembers(&WorkspaceOptions{Owner: teamname})
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]*User, len(memberships))
+	for i, m := range memberships {
+		members[i] = &User{Account: m.User}
+	}
+
+	return members, nil
+}
+
+// Followers has no workspace counterpart and still uses the /teams endpoint.
 func (t *Teams) Followers(teamname string) (interface{}, error) {
 	urlStr := t.c.requestUrl("/teams/%s/followers", teamname)
 	return t.c.execute("GET", urlStr, "")
 }
 
+// Following has no workspace counterpart and still uses the /teams endpoint.
 func (t *Teams) Following(teamname string) (interface{}, error) {
 	urlStr := t.c.requestUrl("/teams/%s/following", teamname)
 	return t.c.execute("GET", urlStr, "")
 }
 
 func (t *Teams) Repositories(teamname string) (interface{}, error) {
-	urlStr := t.c.requestUrl("/teams/%s/repositories", teamname)
-	return t.c.execute("GET", urlStr, "")
+	return t.c.Workspaces.repositories(&WorkspaceOptions{Owner: teamname})
 }
 
 // Projects returns a list of project objects for the given team.
 func (t *Teams) Projects(teamname string) (interface{}, error) {
-	urlStr := t.c.requestUrl("/teams/%s/projects/", teamname)
-	return t.c.execute("GET", urlStr, "")
+	return t.c.Workspaces.projects(teamname)
 }
 
 // ProjectNames returns a list of project names for the given team.
 func (t *Teams) ProjectNames(teamname string) ([]string, error) {
-	urlStr := t.c.requestUrl("/teams/%s/projects/", teamname)
-	response, err := t.c.execute("GET", urlStr, "")
+	list, err := t.c.Workspaces.ListProjects(&WorkspaceOptions{Owner: teamname})
 	if err != nil {
 		return nil, err
 	}
 
-	list := response.(map[string]interface{})["values"].([]interface{})
 	projects := make([]string, len(list))
-
-	for i, l := range list {
-		projects[i] = l.(map[string]interface{})["name"].(string)
+	for i, p := range list {
+		projects[i] = p.Name
 	}
 
 	return projects, nil
@@ -60,6 +127,36 @@ func (t *Teams) ProjectNames(teamname string) ([]string, error) {
 
 // ProjectInfo return information on a specific project
 func (t *Teams) ProjectInfo(teamname, projectKey string) (interface{}, error) {
-	urlStr := t.c.requestUrl("/teams/%s/projects/%s", teamname, projectKey)
-	return t.c.execute("GET", urlStr, "")
+	return t.c.Workspaces.project(teamname, projectKey)
+}
+
+// teamPage converts the values of a workspace page with toAccount, keeping the paging fields.
+func teamPage(response interface{}, toAccount func(map[string]interface{}) interface{}) (interface{}, error) {
+	page, ok := response.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected response of type %T", response)
+	}
+
+	values, _ := page["values"].([]interface{})
+	for i, v := range values {
+		value, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected value of type %T", v)
+		}
+		values[i] = toAccount(value)
+	}
+
+	return page, nil
+}
+
+// teamAccount reshapes a workspace into the team account that /teams returned for it.
+func teamAccount(workspace map[string]interface{}) interface{} {
+	return map[string]interface{}{
+		"type":         AccountTypeTeam,
+		"uuid":         workspace["uuid"],
+		"username":     workspace["slug"],
+		"display_name": workspace["name"],
+		"created_on":   workspace["created_on"],
+		"links":        workspace["links"],
+	}
 }
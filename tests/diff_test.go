@@ -0,0 +1,305 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/ktrysmt/go-bitbucket"
+)
+
+const sampleDiff = `diff --git a/main.go b/main.go
+index 83db48f..bf269f4 100644
+--- a/main.go
++++ b/main.go
+@@ -1,4 +1,5 @@ package main
+ import "fmt"
+-func a() {}
++func a() { fmt.Println("a") }
++func b() {}
+
+ func main() {}
+diff --git a/docs/new file.md b/docs/new file.md
+new file mode 100644
+index 0000000..e69de29
+--- /dev/null
++++ b/docs/new file.md
+@@ -0,0 +1,2 @@
++# Title
++no newline
+\ No newline at end of file
+diff --git a/old.txt b/old.txt
+deleted file mode 100644
+index 3b18e51..0000000
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+diff --git a/a.go b/b.go
+similarity index 90%
+rename from a.go
+rename to b.go
+index 1111111..2222222 100644
+--- a/a.go
++++ b/b.go
+@@ -10,2 +10,2 @@ func x() {
+ keep
+-old
++new
+diff --git a/logo.png b/logo.png
+index 5d7a1b2..c3d9e8f 100644
+Binary files a/logo.png and b/logo.png differ
+diff --git a/run.sh b/run.sh
+old mode 100644
+new mode 100755
+`
+
+func TestParseDiff(t *testing.T) {
+
+	files, err := bitbucket.ParseDiff([]byte(sampleDiff))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 6 {
+		t.Fatalf("expected 6 files, got %d", len(files))
+	}
+
+	modified := files[0]
+	if modified.Path() != "main.go" || modified.Status() != "modified" {
+		t.Errorf("unexpected modified file %q (%s)", modified.Path(), modified.Status())
+	}
+	if added, removed := modified.Stats(); added != 2 || removed != 1 {
+		t.Errorf("expected +2 -1, got +%d -%d", added, removed)
+	}
+	lines := modified.Hunks[0].Lines
+	if modified.Hunks[0].Section != "package main" {
+		t.Errorf("unexpected hunk section %q", modified.Hunks[0].Section)
+	}
+	if lines[2].Type != bitbucket.DiffLineAdded || lines[2].NewLine != 2 || lines[2].OldLine != 0 {
+		t.Errorf("unexpected added line %+v", lines[2])
+	}
+	if lines[5].Type != bitbucket.DiffLineContext || lines[5].OldLine != 4 || lines[5].NewLine != 5 {
+		t.Errorf("unexpected context line %+v", lines[5])
+	}
+
+	created := files[1]
+	if !created.IsNew || created.OldPath != "" || created.NewPath != "docs/new file.md" {
+		t.Errorf("unexpected new file %+v", created)
+	}
+	if !created.Hunks[0].Lines[1].NoNewlineAtEOF {
+		t.Error("missing no newline at end of file marker")
+	}
+
+	deleted := files[2]
+	if !deleted.IsDeleted || deleted.Path() != "old.txt" || deleted.NewPath != "" {
+		t.Errorf("unexpected deleted file %+v", deleted)
+	}
+
+	renamed := files[3]
+	if !renamed.IsRename || renamed.OldPath != "a.go" || renamed.NewPath != "b.go" || renamed.Similarity != 90 {
+		t.Errorf("unexpected renamed file %+v", renamed)
+	}
+	if renamed.Hunks[0].Lines[1].OldLine != 11 {
+		t.Errorf("unexpected removed line %+v", renamed.Hunks[0].Lines[1])
+	}
+
+	if !files[4].IsBinary || len(files[4].Hunks) != 0 {
+		t.Errorf("unexpected binary file %+v", files[4])
+	}
+
+	if !files[5].ModeChanged() || files[5].NewMode != "100755" {
+		t.Errorf("unexpected mode change %+v", files[5])
+	}
+}
+
+func TestParseDiffTruncatedHunk(t *testing.T) {
+
+	_, err := bitbucket.ParseDiff([]byte("diff --git a/x b/x\n--- a/x\n+++ b/x\n@@ -1,3 +1,3 @@\n a\n"))
+	if err == nil {
+		t.Error("expected an error for a truncated hunk")
+	}
+}
+
+const samplePatch = `From 0123456789abcdef0123456789abcdef01234567 Mon Sep 17 00:00:00 2001
+From: Jane Doe <jane@example.com>
+Date: Tue, 1 Jan 2019 10:00:00 +0100
+Subject: [PATCH 1/2] Add greeting
+ to the readme
+
+Explain why the greeting
+is needed.
+---
+ README.md | 1 +
+ 1 file changed, 1 insertion(+)
+
+diff --git a/README.md b/README.md
+index 83db48f..bf269f4 100644
+--- a/README.md
++++ b/README.md
+@@ -1 +1,2 @@
+ # Project
++Hello
+-- 
+2.20.1
+
+From fedcba9876543210fedcba9876543210fedcba98 Mon Sep 17 00:00:00 2001
+From: =?UTF-8?q?J=C3=B6rg?= <jorg@example.com>
+Date: Wed, 2 Jan 2019 11:00:00 +0000
+Subject: [PATCH 2/2] Remove notes
+
+---
+ NOTES | 2 --
+ 1 file changed, 2 deletions(-)
+
+diff --git a/NOTES b/NOTES
+deleted file mode 100644
+index 3b18e51..0000000
+--- a/NOTES
++++ /dev/null
+@@ -1,2 +0,0 @@
+-todo
+---
+-- 
+2.20.1
+`
+
+func TestParsePatch(t *testing.T) {
+
+	commits, err := bitbucket.ParsePatch([]byte(samplePatch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("expected 2 commits, got %d", len(commits))
+	}
+
+	first := commits[0]
+	if first.Hash != "0123456789abcdef0123456789abcdef01234567" {
+		t.Errorf("unexpected hash %q", first.Hash)
+	}
+	if first.AuthorName != "Jane Doe" || first.AuthorEmail != "jane@example.com" {
+		t.Errorf("unexpected author %q <%q>", first.AuthorName, first.AuthorEmail)
+	}
+	if first.Subject != "Add greeting to the readme" {
+		t.Errorf("unexpected subject %q", first.Subject)
+	}
+	if first.Body != "Explain why the greeting\nis needed." {
+		t.Errorf("unexpected body %q", first.Body)
+	}
+	if first.Date.UTC().Hour() != 9 {
+		t.Errorf("unexpected date %s", first.Date)
+	}
+	if len(first.Files) != 1 || first.Files[0].Path() != "README.md" {
+		t.Fatalf("unexpected files %+v", first.Files)
+	}
+
+	second := commits[1]
+	if second.AuthorName != "Jörg" || second.Body != "" {
+		t.Errorf("unexpected author %q or body %q", second.AuthorName, second.Body)
+	}
+	if len(second.Files) != 1 || !second.Files[0].IsDeleted {
+		t.Fatalf("unexpected files %+v", second.Files)
+	}
+	if hunks := second.Files[0].Hunks; len(hunks) != 1 || len(hunks[0].Lines) != 2 {
+		t.Errorf("unexpected hunks %+v", hunks)
+	}
+}
+
+// serveApi points the client at a test server running handler until the test ends.
+func serveApi(t *testing.T, handler http.HandlerFunc) {
+	srv := httptest.NewServer(handler)
+	baseURL := bitbucket.GetApiBaseURL()
+	bitbucket.SetApiBaseURL(srv.URL)
+	t.Cleanup(func() {
+		bitbucket.SetApiBaseURL(baseURL)
+		srv.Close()
+	})
+}
+
+func TestDiffOptionsQuery(t *testing.T) {
+
+	queries := map[string]url.Values{}
+	serveApi(t, func(w http.ResponseWriter, r *http.Request) {
+		queries[r.URL.Path] = r.URL.Query()
+		w.Write([]byte(`{"values":[]}`))
+	})
+	c := bitbucket.NewBasicAuth("user", "password")
+
+	want := url.Values{
+		"path":              {"a.go", "docs"},
+		"context":           {"0"},
+		"ignore_whitespace": {"true"},
+		"binary":            {"false"},
+		"renames":           {"false"},
+		"topic":             {"true"},
+	}
+
+	_, err := c.Repositories.Diff.GetDiff(&bitbucket.DiffOptions{
+		Owner:             "owner",
+		Repo_slug:         "repo",
+		Spec:              "main..feature",
+		Path:              []string{"a.go", "docs"},
+		Context:           bitbucket.Int(0),
+		Ignore_whitespace: true,
+		Binary:            bitbucket.Bool(false),
+		Renames:           bitbucket.Bool(false),
+		Topic:             bitbucket.Bool(true),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	po := &bitbucket.PullRequestsOptions{
+		Owner:             "owner",
+		Repo_slug:         "repo",
+		Id:                "1",
+		Path:              []string{"a.go", "docs"},
+		Context:           bitbucket.Int(0),
+		Ignore_whitespace: true,
+		Binary:            bitbucket.Bool(false),
+		Renames:           bitbucket.Bool(false),
+		Topic:             bitbucket.Bool(true),
+	}
+	if _, err := c.Repositories.PullRequests.Diff(po); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Repositories.PullRequests.Patch(po); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Repositories.PullRequests.Diffstat(po); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{
+		"/repositories/owner/repo/diff/main..feature",
+		"/repositories/owner/repo/pullrequests/1/diff",
+		"/repositories/owner/repo/pullrequests/1/patch",
+		"/repositories/owner/repo/pullrequests/1/diffstat",
+	} {
+		query, ok := queries[path]
+		if !ok {
+			t.Errorf("%s was not requested", path)
+			continue
+		}
+		if query.Encode() != want.Encode() {
+			t.Errorf("%s: unexpected query %q, want %q", path, query.Encode(), want.Encode())
+		}
+	}
+}
+
+func TestDiffOptionsDefaults(t *testing.T) {
+
+	var rawQuery string
+	serveApi(t, func(w http.ResponseWriter, r *http.Request) {
+		rawQuery = r.URL.RawQuery
+	})
+	c := bitbucket.NewBasicAuth("user", "password")
+
+	if _, err := c.Repositories.PullRequests.Diff(&bitbucket.PullRequestsOptions{Owner: "owner", Repo_slug: "repo", Id: "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if rawQuery != "" {
+		t.Errorf("unset options should send no query, got %q", rawQuery)
+	}
+}
@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ktrysmt/go-bitbucket"
+)
+
+const (
+	runningPipeline   = `{"type":"pipeline","uuid":"{run}","state":{"name":"IN_PROGRESS"}}`
+	completedPipeline = `{"type":"pipeline","uuid":"{run}","state":{"name":"COMPLETED","result":{"name":"SUCCESSFUL"}}}`
+)
+
+// servePipeline answers every poll with the running pipeline until the given poll, which completes it. A
+// completeAt of 0 keeps the pipeline running. It returns the number of polls so far.
+func servePipeline(t *testing.T, completeAt int) func() int {
+	polls := 0
+	serveApi(t, func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		if completeAt > 0 && polls >= completeAt {
+			w.Write([]byte(completedPipeline))
+			return
+		}
+		w.Write([]byte(runningPipeline))
+	})
+	return func() int { return polls }
+}
+
+func TestPipelinesWaitCompletes(t *testing.T) {
+
+	polls := servePipeline(t, 3)
+	c := bitbucket.NewBasicAuth("user", "password")
+
+	run, err := c.Repositories.Pipelines.Wait(&bitbucket.PipelinesOptions{Owner: "owner", Repo_slug: "repo", Uuid: "{run}"}, time.Millisecond, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.State.ResultName() != bitbucket.PipelineResultSuccessful || polls() != 3 {
+		t.Errorf("unexpected result %q after %d polls", run.State.ResultName(), polls())
+	}
+}
+
+func TestPipelinesWaitPollsAtDeadline(t *testing.T) {
+
+	polls := servePipeline(t, 0)
+	c := bitbucket.NewBasicAuth("user", "password")
+
+	// An interval longer than the timeout sleeps until the deadline and polls once more there.
+	start := time.Now()
+	run, err := c.Repositories.Pipelines.Wait(&bitbucket.PipelinesOptions{Owner: "owner", Repo_slug: "repo", Uuid: "{run}"}, time.Hour, 50*time.Millisecond)
+	if err == nil {
+		t.Fatal("expected a timeout error")
+	}
+	if run == nil || run.State.IsCompleted() {
+		t.Errorf("expected the running pipeline with the error, got %+v", run)
+	}
+	if polls() != 2 {
+		t.Errorf("expected 2 polls, got %d", polls())
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond || elapsed > time.Second {
+		t.Errorf("unexpected wait of %s", elapsed)
+	}
+}
+
+func TestPipelinesWaitCompletesAtDeadline(t *testing.T) {
+
+	polls := servePipeline(t, 2)
+	c := bitbucket.NewBasicAuth("user", "password")
+
+	run, err := c.Repositories.Pipelines.Wait(&bitbucket.PipelinesOptions{Owner: "owner", Repo_slug: "repo", Uuid: "{run}"}, time.Hour, 50*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !run.State.IsCompleted() || polls() != 2 {
+		t.Errorf("unexpected state %q after %d polls", run.State.Name, polls())
+	}
+}
+
+func TestPipelinesWaitDefaultInterval(t *testing.T) {
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		polls := servePipeline(t, 0)
+		c := bitbucket.NewBasicAuth("user", "password")
+
+		// Without the default the non-positive interval would poll in a tight loop until the deadline.
+		_, err := c.Repositories.Pipelines.Wait(&bitbucket.PipelinesOptions{Owner: "owner", Repo_slug: "repo", Uuid: "{run}"}, interval, 50*time.Millisecond)
+		if err == nil {
+			t.Fatal("expected a timeout error")
+		}
+		if polls() != 2 {
+			t.Errorf("interval %s: expected 2 polls, got %d", interval, polls())
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/ktrysmt/go-bitbucket/pipelinesyml"
+)
+
+const samplePipelines = `image: golang:1.21
+
+definitions:
+  caches:
+    gomod: /go/pkg/mod
+  services:
+    postgres:
+      image: postgres:15
+      memory: 1024
+  steps:
+    - step: &test
+        name: Test
+        caches: [gomod]
+        services: [postgres]
+        script:
+          - go test ./...
+
+pipelines:
+  default:
+    - step: *test
+  branches:
+    main:
+      - parallel:
+          fail-fast: true
+          steps:
+            - step: *test
+            - step:
+                name: Lint
+                image:
+                  name: golangci/golangci-lint
+                  username: $USER
+                script:
+                  - golangci-lint run
+      - stage:
+          name: Deploy
+          deployment: production
+          steps:
+            - step:
+                name: Release
+                trigger: manual
+                artifacts: [dist/**]
+                script:
+                  - pipe: atlassian/aws-s3-deploy:1.1.0
+                    variables:
+                      S3_BUCKET: releases
+  custom:
+    nightly:
+      - variables:
+          - name: TARGET
+            default: staging
+            allowed-values: [staging, production]
+      - step:
+          <<: *test
+          name: Nightly
+          runs-on: self.hosted
+`
+
+func TestParsePipelinesYml(t *testing.T) {
+	config, err := pipelinesyml.Parse([]byte(samplePipelines))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Image.Name != "golang:1.21" {
+		t.Errorf("image = %q", config.Image.Name)
+	}
+	if config.Definitions.Caches["gomod"].Path != "/go/pkg/mod" || config.Definitions.Services["postgres"].Memory != 1024 {
+		t.Errorf("definitions = %+v", config.Definitions)
+	}
+
+	if step := config.Pipelines.Default[0].Step; step.Name != "Test" || step.Script[0].Command != "go test ./..." {
+		t.Errorf("default step = %+v", step)
+	}
+
+	main := config.Pipelines.Branches["main"]
+	parallel := main[0].Parallel
+	if !parallel.FailFast || len(parallel.Steps) != 2 || parallel.Steps[1].Step.Image.Username != "$USER" {
+		t.Errorf("parallel = %+v", parallel)
+	}
+	stage := main[1].Stage
+	release := stage.Steps[0].Step
+	if stage.Deployment != "production" || release.Script[0].Pipe != "atlassian/aws-s3-deploy:1.1.0" || release.Artifacts.Paths[0] != "dist/**" {
+		t.Errorf("stage = %+v", stage)
+	}
+	if steps := main[1].Steps(); len(steps) != 1 || steps[0] != release {
+		t.Errorf("stage steps = %v", steps)
+	}
+
+	nightly := config.Pipelines.Custom["nightly"]
+	if len(nightly[0].Variables[0].AllowedValues) != 2 {
+		t.Errorf("variables = %+v", nightly[0].Variables)
+	}
+	if step := nightly[1].Step; step.Name != "Nightly" || step.Caches[0] != "gomod" || step.RunsOn[0] != "self.hosted" {
+		t.Errorf("merged step = %+v", step)
+	}
+
+	if errs := config.Validate(); errs != nil {
+		t.Errorf("Validate() = %v", errs)
+	}
+}
+
+func TestValidatePipelinesYml(t *testing.T) {
+	config, err := pipelinesyml.Parse([]byte(`pipelines:
+  default:
+    - step:
+        trigger: manual
+        caches: [missing]
+        script:
+          - make
+    - parallel:
+        - step:
+            size: 3x
+            script: [make]
+    - variables:
+        - name: X
+  tags:
+    v*:
+      - step:
+          deployment: production
+          script: [make]
+      - step:
+          deployment: production
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		`default[0].step: cache "missing" is not defined`,
+		`default[0].step: the first step of a pipeline cannot be manual`,
+		`default[1].parallel: parallel needs at least two steps`,
+		`default[1].parallel[0].step: unknown size "3x"`,
+		`default[2]: variables are only allowed as the first item of a custom pipeline`,
+		`tags.v*[1].step: step has no script`,
+		`tags.v*[1].step: deployment "production" is already used by tags.v*[0].step`,
+	}
+	errs := config.Validate()
+	if len(errs) != len(want) {
+		t.Fatalf("Validate() = %v", errs)
+	}
+	for i, err := range errs {
+		if err.Error() != want[i] {
+			t.Errorf("error %d = %q, want %q", i, err, want[i])
+		}
+	}
+}
@@ -1,8 +1,20 @@
 package bitbucket
 
-// User is the sub struct of Client
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// User is the sub struct of Client. It doubles as the typed model of a user account, as returned by
+// GetProfile and Users.GetUser.
 type User struct {
 	c *Client
+
+	Account         `mapstructure:",squash"`
+	Account_status  string
+	Has_2fa_enabled bool
+	Is_staff        bool
 }
 
 // Profile is getting the user data
@@ -11,8 +23,47 @@ func (u *User) Profile() (interface{}, error) {
 	return u.c.execute("GET", urlStr, "")
 }
 
+// GetProfile returns the authenticated user.
+func (u *User) GetProfile() (*User, error) {
+	response, err := u.Profile()
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeUser(response)
+}
+
 // Emails is getting user's emails
 func (u *User) Emails() (interface{}, error) {
 	urlStr := GetApiBaseURL() + "/user/emails"
 	return u.c.execute("GET", urlStr, "")
 }
+
+// RepositoryPermissions returns the effective permissions of the authenticated user on all repositories it
+// can access. A non empty permission, ie. PermissionAdmin, only returns the repositories with that level.
+func (u *User) RepositoryPermissions(permission string) ([]*RepositoryPermission, error) {
+	urlStr := GetApiBaseURL() + "/user/permissions/repositories"
+	if permission != "" {
+		urlStr += "?" + url.Values{"q": {fmt.Sprintf("permission=%q", permission)}}.Encode()
+	}
+	response, err := u.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var permissions []*RepositoryPermission
+	if err := decodeValues(response, &permissions); err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+func decodeUser(response interface{}) (*User, error) {
+	var user = new(User)
+	if err := decodeObject(response, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -10,6 +10,16 @@ func (u *Users) Get(t string) (interface{}, error) {
 	return u.c.execute("GET", urlStr, "")
 }
 
+// GetUser returns the user with the given username, account id or UUID.
+func (u *Users) GetUser(t string) (*User, error) {
+	response, err := u.Get(t)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeUser(response)
+}
+
 func (c *Client) Get(t string) (interface{}, error) {
 
 	urlStr := GetApiBaseURL() + "/users/" + t + "/"
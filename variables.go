@@ -0,0 +1,172 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/k0kubun/pp"
+)
+
+// The scopes of the variable calls of Pipelines. The Scope of the options must be set and picks the fields that
+// are required: Owner for the workspace, Owner and Repo_slug for a repository, and Owner, Repo_slug and the
+// Environment UUID for a deployment environment. Variables of all scopes are PipelineVariable values.
+const (
+	PipelineVariableScopeWorkspace   = "workspace"
+	PipelineVariableScopeRepository  = "repository"
+	PipelineVariableScopeEnvironment = "environment"
+)
+
+// ListVariables returns the variables of the scope. Secured variables come without a Value.
+func (p *Pipelines) ListVariables(vo *PipelineVariableOptions) ([]*PipelineVariable, error) {
+	urlStr, err := p.variablesUrl(vo)
+	if err != nil {
+		return nil, err
+	}
+	response, err := p.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var variables []*PipelineVariable
+	if err := decodeValues(response, &variables); err != nil {
+		return nil, err
+	}
+
+	return variables, nil
+}
+
+// GetVariable returns the variable of the scope with the given Uuid. Deployment environments have no endpoint
+// for a single variable, so their variables are looked up in the list.
+func (p *Pipelines) GetVariable(vo *PipelineVariableOptions) (*PipelineVariable, error) {
+	if vo.Scope == PipelineVariableScopeEnvironment {
+		variables, err := p.ListVariables(vo)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range variables {
+			if v.Uuid == vo.Uuid {
+				return v, nil
+			}
+		}
+		return nil, &BitbucketError{Message: "variable " + vo.Uuid + " not found", StatusCode: http.StatusNotFound}
+	}
+
+	urlStr, err := p.variablesUrl(vo)
+	if err != nil {
+		return nil, err
+	}
+	response, err := p.c.execute(http.MethodGet, urlStr+"/"+vo.Uuid, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodePipelineVariableRepository(response)
+}
+
+// CreateVariable adds a variable to the scope.
+func (p *Pipelines) CreateVariable(vo *PipelineVariableOptions) (*PipelineVariable, error) {
+	urlStr, err := p.variablesUrl(vo)
+	if err != nil {
+		return nil, err
+	}
+	data := p.buildVariableBody(vo)
+	response, err := p.c.execute(http.MethodPost, urlStr, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodePipelineVariableRepository(response)
+}
+
+// UpdateVariable replaces the key, value and secured flag of the variable with the given Uuid.
+func (p *Pipelines) UpdateVariable(vo *PipelineVariableOptions) (*PipelineVariable, error) {
+	urlStr, err := p.variablesUrl(vo)
+	if err != nil {
+		return nil, err
+	}
+	data := p.buildVariableBody(vo)
+	response, err := p.c.execute(http.MethodPut, urlStr+"/"+vo.Uuid, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodePipelineVariableRepository(response)
+}
+
+// DeleteVariable deletes the variable with the given Uuid.
+func (p *Pipelines) DeleteVariable(vo *PipelineVariableOptions) error {
+	urlStr, err := p.variablesUrl(vo)
+	if err != nil {
+		return err
+	}
+	_, err = p.c.execute(http.MethodDelete, urlStr+"/"+vo.Uuid, "")
+	return err
+}
+
+// UpsertVariable adds the variable to the scope, or updates the existing variable with the same Key, so it can
+// be called on every run. An unsecured variable that already has the value is left untouched; a secured one
+// cannot be read back and is always updated.
+func (p *Pipelines) UpsertVariable(vo *PipelineVariableOptions) (*PipelineVariable, error) {
+	variables, err := p.ListVariables(vo)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range variables {
+		if v.Key != vo.Key {
+			continue
+		}
+		if !v.Secured && !vo.Secured && v.Value == vo.Value {
+			return v, nil
+		}
+		update := *vo
+		update.Uuid = v.Uuid
+		return p.UpdateVariable(&update)
+	}
+
+	return p.CreateVariable(vo)
+}
+
+// variablesUrl returns the variables URL of the Scope, and an error when the Scope is unknown or a field it
+// needs is empty, so a missing field never widens the scope.
+func (p *Pipelines) variablesUrl(vo *PipelineVariableOptions) (string, error) {
+	switch {
+	case vo.Scope != PipelineVariableScopeWorkspace && vo.Scope != PipelineVariableScopeRepository &&
+		vo.Scope != PipelineVariableScopeEnvironment:
+		return "", fmt.Errorf("unknown pipeline variable scope %q", vo.Scope)
+	case vo.Owner == "":
+		return "", fmt.Errorf("the %s scope needs an Owner", vo.Scope)
+	case vo.Scope == PipelineVariableScopeWorkspace:
+		return p.c.requestUrl("/workspaces/%s/pipelines-config/variables", vo.Owner), nil
+	case vo.Repo_slug == "":
+		return "", fmt.Errorf("the %s scope needs a Repo_slug", vo.Scope)
+	case vo.Scope == PipelineVariableScopeRepository:
+		return p.c.requestUrl("/repositories/%s/%s/pipelines_config/variables", vo.Owner, vo.Repo_slug), nil
+	case vo.Environment == "":
+		return "", fmt.Errorf("the %s scope needs an Environment", vo.Scope)
+	default:
+		return p.c.requestUrl("/repositories/%s/%s/deployments_config/environments/%s/variables", vo.Owner, vo.Repo_slug, vo.Environment), nil
+	}
+}
+
+func (p *Pipelines) buildVariableBody(vo *PipelineVariableOptions) string {
+
+	body := map[string]interface{}{}
+
+	if vo.Uuid != "" {
+		body["uuid"] = vo.Uuid
+	}
+	body["key"] = vo.Key
+	body["value"] = vo.Value
+	body["secured"] = vo.Secured
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		pp.Println(err)
+		os.Exit(9)
+	}
+
+	return string(data)
+}
@@ -0,0 +1,200 @@
+package bitbucket
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// Workspace is a workspace, the successor of teams. Every repository and project belongs to one.
+type Workspace struct {
+	Type       string
+	Uuid       string
+	Slug       string
+	Name       string
+	Is_private bool
+	Created_on string
+	Updated_on string
+	Links      map[string]interface{}
+}
+
+// WorkspaceMembership links a user to a workspace. Permission, one of owner, collaborator or member, is only
+// set by the permissions calls.
+type WorkspaceMembership struct {
+	Type       string
+	Permission string
+	User       Account
+	Workspace  Workspace
+	Links      map[string]interface{}
+}
+
+type Workspaces struct {
+	c *Client
+}
+
+// List returns the workspaces the authenticated user has access to, narrowed down by Role, one of owner,
+// collaborator or member, and the BBQL Query.
+func (w *Workspaces) List(wo *WorkspaceOptions) ([]*Workspace, error) {
+	response, err := w.list(wo)
+	if err != nil {
+		return nil, err
+	}
+
+	var workspaces []*Workspace
+	if err := decodeValues(response, &workspaces); err != nil {
+		return nil, err
+	}
+
+	return workspaces, nil
+}
+
+// Get returns the workspace given as Owner.
+func (w *Workspaces) Get(wo *WorkspaceOptions) (*Workspace, error) {
+	response, err := w.get(wo.Owner)
+	if err != nil {
+		return nil, err
+	}
+
+	var workspace = new(Workspace)
+	if err := decodeObject(response, workspace); err != nil {
+		return nil, err
+	}
+
+	return workspace, nil
+}
+
+// ListMembers returns the members of the workspace.
+func (w *Workspaces) ListMembers(wo *WorkspaceOptions) ([]*WorkspaceMembership, error) {
+	response, err := w.members(wo.Owner)
+	if err != nil {
+		return nil, err
+	}
+
+	var members []*WorkspaceMembership
+	if err := decodeValues(response, &members); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// GetMember returns the membership of the Member, given by account id or UUID, in the workspace.
+func (w *Workspaces) GetMember(wo *WorkspaceOptions) (*WorkspaceMembership, error) {
+	urlStr := w.c.requestUrl("/workspaces/%s/members/%s", wo.Owner, wo.Member)
+	response, err := w.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var member = new(WorkspaceMembership)
+	if err := decodeObject(response, member); err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// ListPermissions returns the members of the workspace with their permission. Query takes a BBQL filter,
+// ie. permission="owner".
+func (w *Workspaces) ListPermissions(wo *WorkspaceOptions) ([]*WorkspaceMembership, error) {
+	urlStr := w.c.requestUrl("/workspaces/%s/permissions", wo.Owner)
+	urlStr += w.buildWorkspaceQuery(wo, false)
+	response, err := w.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var permissions []*WorkspaceMembership
+	if err := decodeValues(response, &permissions); err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+// ListRepositoryPermissions returns the user permissions on all repositories of the workspace, or only on
+// Repo_slug if it is set.
+func (w *Workspaces) ListRepositoryPermissions(wo *WorkspaceOptions) ([]*RepositoryPermission, error) {
+	urlStr := w.c.requestUrl("/workspaces/%s/permissions/repositories", wo.Owner)
+	if wo.Repo_slug != "" {
+		urlStr += "/" + wo.Repo_slug
+	}
+	urlStr += w.buildWorkspaceQuery(wo, false)
+	response, err := w.c.execute(http.MethodGet, urlStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var permissions []*RepositoryPermission
+	if err := decodeValues(response, &permissions); err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+// ListProjects returns the projects of the workspace.
+func (w *Workspaces) ListProjects(wo *WorkspaceOptions) ([]*Project, error) {
+	return w.c.Projects.List(&ProjectOptions{Owner: wo.Owner})
+}
+
+// ListRepositories returns the repositories of the workspace, narrowed down by Role and Query.
+func (w *Workspaces) ListRepositories(wo *WorkspaceOptions) (*RepositoriesPage, error) {
+	response, err := w.repositories(wo)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeRepositoriesPage(response)
+}
+
+// The untyped calls below are shared with Teams, which returns the raw responses.
+
+func (w *Workspaces) list(wo *WorkspaceOptions) (interface{}, error) {
+	urlStr := w.c.requestUrl("/workspaces") + w.buildWorkspaceQuery(wo, true)
+	return w.c.execute(http.MethodGet, urlStr, "")
+}
+
+func (w *Workspaces) get(workspace string) (interface{}, error) {
+	urlStr := w.c.requestUrl("/workspaces/%s", workspace)
+	return w.c.execute(http.MethodGet, urlStr, "")
+}
+
+func (w *Workspaces) members(workspace string) (interface{}, error) {
+	urlStr := w.c.requestUrl("/workspaces/%s/members", workspace)
+	return w.c.execute(http.MethodGet, urlStr, "")
+}
+
+func (w *Workspaces) project(workspace, projectKey string) (interface{}, error) {
+	urlStr := w.c.requestUrl("/workspaces/%s/projects/%s", workspace, projectKey)
+	return w.c.execute(http.MethodGet, urlStr, "")
+}
+
+func (w *Workspaces) projects(workspace string) (interface{}, error) {
+	urlStr := w.c.requestUrl("/workspaces/%s/projects", workspace)
+	return w.c.execute(http.MethodGet, urlStr, "")
+}
+
+func (w *Workspaces) repositories(wo *WorkspaceOptions) (interface{}, error) {
+	urlStr := w.c.requestUrl("/repositories/%s", wo.Owner) + w.buildWorkspaceQuery(wo, true)
+	return w.c.execute(http.MethodGet, urlStr, "")
+}
+
+func (w *Workspaces) buildWorkspaceQuery(wo *WorkspaceOptions, withRole bool) string {
+
+	p := url.Values{}
+
+	if withRole && wo.Role != "" {
+		p.Add("role", wo.Role)
+	}
+	if wo.Query != "" {
+		p.Add("q", wo.Query)
+	}
+	if wo.Sort != "" {
+		p.Add("sort", wo.Sort)
+	}
+
+	if len(p) == 0 {
+		return ""
+	}
+	return "?" + p.Encode()
+}